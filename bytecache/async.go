@@ -0,0 +1,112 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// FullPolicy determines what SetAsync does when the write buffer is full.
+type FullPolicy int
+
+const (
+	// DropWhenFull discards writes that don't fit in the buffer.
+	DropWhenFull FullPolicy = iota
+	// BlockWhenFull waits until the buffer has room.
+	BlockWhenFull
+)
+
+// AsyncWriter applies writes to a [Cache] from a background goroutine, so that
+// producers don't wait on shard lock contention. Writes become visible
+// eventually rather than when SetAsync returns.
+type AsyncWriter struct {
+	cache  *Cache
+	policy FullPolicy
+
+	// lock guards closed and prevents writes from being sent on writes after
+	// it has been closed.
+	lock    sync.RWMutex
+	closed  bool
+	writes  chan asyncWrite
+	done    chan struct{}
+	dropped uint64
+}
+
+type asyncWrite struct {
+	key, value []byte
+}
+
+// NewAsyncWriter starts a background worker that applies writes to c. At most
+// bufferSize writes are queued; policy decides what happens beyond that.
+func NewAsyncWriter(c *Cache, bufferSize int, policy FullPolicy) *AsyncWriter {
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+	w := &AsyncWriter{
+		cache:  c,
+		policy: policy,
+		writes: make(chan asyncWrite, bufferSize),
+		done:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// SetAsync queues key/value to be stored. key and value are copied, so the
+// caller may reuse them immediately. It returns false if the write was
+// dropped, either because the buffer was full under [DropWhenFull] or because
+// the writer has been closed.
+func (w *AsyncWriter) SetAsync(key, value []byte) bool {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	if w.closed {
+		atomic.AddUint64(&w.dropped, 1)
+		return false
+	}
+
+	write := asyncWrite{
+		key:   append([]byte(nil), key...),
+		value: append([]byte(nil), value...),
+	}
+	if w.policy == BlockWhenFull {
+		w.writes <- write
+		return true
+	}
+	select {
+	case w.writes <- write:
+		return true
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+		return false
+	}
+}
+
+// Dropped returns the number of writes that were discarded.
+func (w *AsyncWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Close stops accepting writes, waits for every queued write to be applied to
+// the cache, and stops the background worker. It is safe to call more than
+// once.
+func (w *AsyncWriter) Close() {
+	w.lock.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.writes)
+	}
+	w.lock.Unlock()
+
+	<-w.done
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	for write := range w.writes {
+		w.cache.Set(write.key, write.value)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsyncWriterDropWhenFull(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 20)
+	w := NewAsyncWriter(c, 2, DropWhenFull)
+
+	// Hold the shard lock so the worker stalls on its first write.
+	s := c.shard(sameShardKey(0))
+	s.mu.Lock()
+	var accepted []int
+	for i := 0; i < 10; i++ {
+		if w.SetAsync(sameShardKey(i), []byte{byte(i)}) {
+			accepted = append(accepted, i)
+		}
+	}
+	s.mu.Unlock()
+
+	// The worker holds at most one write and the buffer holds two.
+	require.LessOrEqual(len(accepted), 3)
+	require.Equal(uint64(10-len(accepted)), w.Dropped())
+
+	w.Close()
+	for _, i := range accepted {
+		require.True(c.Has(sameShardKey(i)))
+	}
+	require.False(w.SetAsync([]byte("late"), nil))
+}
+
+func TestAsyncWriterBlockWhenFull(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 20)
+	w := NewAsyncWriter(c, 1, BlockWhenFull)
+
+	s := c.shard(sameShardKey(0))
+	s.mu.Lock()
+	var (
+		finished = make(chan struct{})
+		accepted int
+	)
+	go func() {
+		defer close(finished)
+		for i := 0; i < 3; i++ {
+			if w.SetAsync(sameShardKey(i), []byte{byte(i)}) {
+				accepted++
+			}
+		}
+	}()
+
+	select {
+	case <-finished:
+		require.FailNow("SetAsync should block while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	s.mu.Unlock()
+	<-finished
+	require.Equal(3, accepted)
+
+	w.Close()
+	require.Zero(w.Dropped())
+	for i := 0; i < 3; i++ {
+		require.True(c.Has(sameShardKey(i)))
+	}
+}
+
+func TestAsyncWriterCloseFlushes(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 20)
+	w := NewAsyncWriter(c, 100, DropWhenFull)
+	for i := 0; i < 100; i++ {
+		require.True(w.SetAsync([]byte{byte(i)}, []byte{byte(i)}))
+	}
+	w.Close()
+	w.Close()
+
+	for i := 0; i < 100; i++ {
+		v, ok := c.HasGet(nil, []byte{byte(i)})
+		require.True(ok)
+		require.Equal([]byte{byte(i)}, v)
+	}
+}
@@ -4,13 +4,35 @@
 package bytecache
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"sync"
 	"sync/atomic"
+
+	"github.com/luxfi/cache"
+)
+
+var (
+	_ cache.SizeReporter = (*Cache)(nil)
+	_ cache.ByteSized    = (*Cache)(nil)
 )
 
+// ErrDecode is returned by GetChecked for a cached value that the cache's
+// [Codec] or [Transform] fails to decode.
+var ErrDecode = errors.New("failed to decode cached value")
+
 const (
+	// numShards is the number of shards of caches created by New.
 	numShards = 256
-	shardMask = numShards - 1
+
+	// sharedEvictionSamples is the number of non-empty shards inspected when
+	// choosing a victim in shared-capacity mode.
+	sharedEvictionSamples = 8
+
+	// resetRetainDivisor sets the fraction of a shard's peak number of
+	// entries that Reset preallocates room for in its new map.
+	resetRetainDivisor = 2
 )
 
 // Stats contains cache performance metrics.
@@ -26,11 +48,32 @@ type Stats struct {
 // Cache is a high-performance sharded LRU byte cache.
 // It provides O(1) lookups with minimal lock contention.
 type Cache struct {
-	shards   [numShards]*byteShard
+	// shards has a power of two length, and mask is one less than it.
+	shards   []*byteShard
+	mask     int
 	maxBytes int64
 	getCalls uint64
 	setCalls uint64
 	misses   uint64
+
+	// shared is set when shards borrow capacity from a global budget rather
+	// than each being limited to an equal share of maxBytes.
+	shared bool
+	// bytes is the total size and entries the total number of entries across
+	// shards. Both are updated with the shard lock held but read lock-free.
+	bytes   int64
+	entries int64
+	// clock orders accesses across shards in shared-capacity mode.
+	clock uint64
+	// evictCursor rotates the starting shard of eviction sampling.
+	evictCursor uint64
+
+	// codec, if non-nil, encodes values on Set and decodes them on Get.
+	codec Codec
+	// consistent selects shards with ConsistentShard rather than by XOR.
+	consistent bool
+	// copyPolicy decides whether Set and Get copy values.
+	copyPolicy CopyPolicy
 }
 
 type byteShard struct {
@@ -39,126 +82,376 @@ type byteShard struct {
 	head, tail  *byteEntry
 	currentSize int64
 	maxSize     int64
+	// peak is the largest number of entries held since the shard was last
+	// reset.
+	peak int
+
+	// hot, if non-nil, tracks the most accessed keys of the shard.
+	hot *cache.HotKeyTracker[string]
 }
 
 type byteEntry struct {
 	key        string
-	value      []byte
+	value      *byteValue
 	size       int
+	seq        uint64
 	prev, next *byteEntry
+	// hits is the number of accesses since the entry was inserted, which is
+	// persisted to reload the most accessed entries first.
+	hits uint32
 }
 
 // New creates a new byte cache with the given max size in bytes.
 func New(maxBytes int) *Cache {
+	return NewWithExpectedEntries(maxBytes, 0)
+}
+
+// NewWithExpectedEntries creates a new byte cache whose shard maps are
+// pre-sized to hold expectedEntries in total, avoiding rehashing while the
+// cache warms up.
+func NewWithExpectedEntries(maxBytes, expectedEntries int) *Cache {
+	return NewWithOptions(Options{MaxBytes: maxBytes, ExpectedEntries: expectedEntries})
+}
+
+// newCache creates a byte cache split into shards shards, which must be a
+// power of two, each limited to an equal share of maxBytes.
+func newCache(maxBytes, expectedEntries, shards int) *Cache {
 	if maxBytes <= 0 {
 		maxBytes = 1
 	}
-	c := &Cache{maxBytes: int64(maxBytes)}
-	perShard := int64(maxBytes) / numShards
+	c := &Cache{
+		shards:   make([]*byteShard, shards),
+		mask:     shards - 1,
+		maxBytes: int64(maxBytes),
+	}
+	perShard := int64(maxBytes) / int64(shards)
 	if perShard < 1 {
 		perShard = 1
 	}
+	perShardEntries := max(expectedEntries, 0) / shards
 	for i := range c.shards {
 		c.shards[i] = &byteShard{
-			items:   make(map[string]*byteEntry),
+			items:   make(map[string]*byteEntry, perShardEntries),
 			maxSize: perShard,
 		}
 	}
 	return c
 }
 
+// NewShared creates a byte cache whose shards share a single global budget of
+// maxBytes.
+//
+// With [New], each shard is limited to maxBytes/256, so a skewed key
+// distribution evicts from hot shards while capacity in cold shards goes
+// unused. In shared mode a shard may grow past its nominal share as long as
+// the total across all shards stays under maxBytes. When the total is
+// exceeded, the least recently used entry of the oldest of a small sample of
+// shard tails is evicted, approximating a global LRU.
+//
+// This costs extra coordination: every Get and Set increments a shared atomic
+// access clock and total byte counter, and eviction briefly locks shards other
+// than the one being written. Under heavy write concurrency this contends more
+// than [New], which never touches state outside a single shard.
+func NewShared(maxBytes int) *Cache {
+	return NewWithOptions(Options{MaxBytes: maxBytes, Shared: true})
+}
+
+// NewWithCompression creates a byte cache that compresses values with codec
+// on Set and decompresses them on Get. The compressed size is what counts
+// against maxBytes. A nil codec disables compression.
+func NewWithCompression(maxBytes int, codec Codec) *Cache {
+	return NewWithOptions(Options{MaxBytes: maxBytes, Codec: codec})
+}
+
 func (c *Cache) shard(key []byte) *byteShard {
+	s := c.shards[c.shardIndex(key)]
+	if s.hot != nil && s.hot.Sampled() {
+		s.hot.Add(string(key))
+	}
+	return s
+}
+
+// shardIndex returns the index of the shard that key maps to.
+func (c *Cache) shardIndex(key []byte) int {
+	if c.consistent {
+		return ConsistentShard(key, len(c.shards))
+	}
+	if len(c.shards) > 256 {
+		// XORing the bytes only yields 256 distinct values.
+		h := uint64(fnvOffset64)
+		for _, b := range key {
+			h ^= uint64(b)
+			h *= fnvPrime64
+		}
+		return int(h) & c.mask
+	}
 	h := uint8(0)
 	for _, b := range key {
 		h ^= b
 	}
-	return c.shards[h&shardMask]
+	return int(h) & c.mask
 }
 
 // Reset clears all cached entries.
+//
+// Each shard's new map is preallocated for half the most entries it held since
+// it was last reset, so that refilling the cache does not pay for growing the
+// maps from scratch again.
 func (c *Cache) Reset() {
 	for _, s := range c.shards {
-		s.mu.Lock()
-		s.items = make(map[string]*byteEntry)
-		s.head, s.tail = nil, nil
-		s.currentSize = 0
-		s.mu.Unlock()
+		c.resetShard(s)
 	}
 }
 
+// ResetPresized clears all cached entries, like Reset, preallocating the
+// shard maps for hint entries in total instead of sizing them from their
+// previous contents. A hint of 0 releases the maps' memory entirely.
+func (c *Cache) ResetPresized(hint int) {
+	perShard := max(hint, 0) / len(c.shards)
+	for _, s := range c.shards {
+		c.resetShardTo(s, perShard)
+	}
+}
+
+// ResetShard clears the shard that key maps to. Every entry of that shard is
+// removed, not only key, while the other shards are left untouched.
+func (c *Cache) ResetShard(key []byte) {
+	c.resetShard(c.shard(key))
+}
+
+// ResetShards clears every shard that one of keys maps to, as ResetShard
+// does. Each shard is cleared at most once.
+func (c *Cache) ResetShards(keys [][]byte) {
+	reset := make([]bool, len(c.shards))
+	for _, key := range keys {
+		if i := c.shardIndex(key); !reset[i] {
+			reset[i] = true
+			c.resetShard(c.shards[i])
+		}
+	}
+}
+
+func (c *Cache) resetShard(s *byteShard) {
+	c.resetShardTo(s, -1)
+}
+
+// resetShardTo clears s, preallocating its new map for entries entries, or
+// from its peak if entries is negative.
+func (c *Cache) resetShardTo(s *byteShard, entries int) {
+	s.mu.Lock()
+	c.clearShard(s, entries)
+	s.mu.Unlock()
+}
+
+// clearShard is resetShardTo with s.mu held.
+func (c *Cache) clearShard(s *byteShard, entries int) {
+	if entries < 0 {
+		entries = max(s.peak, len(s.items)) / resetRetainDivisor
+	}
+	atomic.AddInt64(&c.bytes, -s.currentSize)
+	atomic.AddInt64(&c.entries, -int64(len(s.items)))
+	s.items = make(map[string]*byteEntry, entries)
+	s.head, s.tail = nil, nil
+	s.currentSize = 0
+	s.peak = 0
+}
+
 // Del removes a key from the cache.
 func (c *Cache) Del(key []byte) {
 	s := c.shard(key)
-	k := string(key)
 	s.mu.Lock()
-	if e, ok := s.items[k]; ok {
-		s.unlink(e)
-		s.currentSize -= int64(e.size)
-		delete(s.items, k)
+	if e, ok := s.items[string(key)]; ok {
+		c.remove(s, e)
+	}
+	s.mu.Unlock()
+}
+
+// GetDel removes key and returns its value, appended to dst[:0] like HasGet,
+// reporting whether it was present. The lookup and removal happen under the
+// shard lock, so of several goroutines calling GetDel for the same key only
+// one receives the value.
+func (c *Cache) GetDel(dst, key []byte) ([]byte, bool) {
+	atomic.AddUint64(&c.getCalls, 1)
+	s := c.shard(key)
+
+	s.mu.Lock()
+	e, ok := s.items[string(key)]
+	if !ok {
+		s.mu.Unlock()
+		atomic.AddUint64(&c.misses, 1)
+		if dst == nil {
+			return nil, false
+		}
+		return dst[:0], false
 	}
+	v := e.value
+	v.acquire()
+	c.remove(s, e)
 	s.mu.Unlock()
+
+	val, ok := c.decode(v.buf)
+	if ok {
+		val = append(dst[:0], val...)
+	}
+	v.release()
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		if dst == nil {
+			return nil, false
+		}
+		return dst[:0], false
+	}
+	return val, true
 }
 
-// Has reports whether a key exists.
+// Has reports whether a key exists. It doesn't allocate.
 func (c *Cache) Has(key []byte) bool {
 	s := c.shard(key)
-	k := string(key)
 	s.mu.RLock()
-	_, ok := s.items[k]
+	_, ok := s.items[string(key)]
 	s.mu.RUnlock()
 	return ok
 }
 
-// HasGet returns the value and whether it exists.
+// Touch marks the entry with the key as most recently used in its shard
+// without copying its value, returning whether the key exists.
+func (c *Cache) Touch(key []byte) bool {
+	s := c.shard(key)
+	s.mu.Lock()
+	e, ok := s.items[string(key)]
+	if ok {
+		c.touch(s, e)
+	}
+	s.mu.Unlock()
+	return ok
+}
+
+// HasGet returns the value and whether it exists. Like Get, it copies the
+// value into dst unless the copy policy shares it.
 func (c *Cache) HasGet(dst, key []byte) ([]byte, bool) {
+	val, ok, _ := c.hasGet(dst, key, c.copyPolicy != AlwaysCopy)
+	return val, ok
+}
+
+// GetChecked is like HasGet, but also returns the error of a value that is
+// cached but fails to decode, which HasGet reports as missing. The error wraps
+// ErrDecode and the error returned by the [Codec] or [Transform].
+func (c *Cache) GetChecked(dst, key []byte) ([]byte, bool, error) {
+	return c.hasGet(dst, key, c.copyPolicy != AlwaysCopy)
+}
+
+// hasGet looks up key, returning the cache's own buffer rather than a copy in
+// dst if share is set and the value isn't encoded.
+func (c *Cache) hasGet(dst, key []byte, share bool) ([]byte, bool, error) {
 	atomic.AddUint64(&c.getCalls, 1)
 	s := c.shard(key)
-	k := string(key)
 
 	s.mu.Lock()
-	e, ok := s.items[k]
+	e, ok := s.items[string(key)]
 	if ok {
-		s.moveToFront(e)
-		val := e.value
+		c.touch(s, e)
+		v := e.value
+		v.acquire()
 		s.mu.Unlock()
-		if dst == nil {
-			return append([]byte(nil), val...), true
+
+		if share && c.codec == nil {
+			// As in GetNoCopy, the reference is never released so that the
+			// buffer is never recycled.
+			return v.buf, true, nil
 		}
-		return append(dst[:0], val...), true
+		val, err := c.decodeErr(v.buf)
+		if err == nil {
+			if dst == nil {
+				val = append([]byte(nil), val...)
+			} else {
+				val = append(dst[:0], val...)
+			}
+		}
+		v.release()
+		if err == nil {
+			return val, true, nil
+		}
+		atomic.AddUint64(&c.misses, 1)
+		return dst[:0], false, err
 	}
 	s.mu.Unlock()
 
 	atomic.AddUint64(&c.misses, 1)
 	if dst == nil {
-		return nil, false
+		return nil, false, nil
 	}
-	return dst[:0], false
+	return dst[:0], false, nil
 }
 
-// Get looks up a value by key, copying into dst if provided.
+// Get looks up a value by key, copying into dst if provided. Under the NoCopy
+// and CopyOnWrite policies, the cache's storage is returned instead.
 func (c *Cache) Get(dst, key []byte) []byte {
+	v, _ := c.HasGet(dst, key)
+	return v
+}
+
+// GetInto copies the value for key into dst without allocating.
+//
+// On a hit where the value fits, it returns the number of bytes written and
+// true. On a miss it returns (0, false). If dst is too small to hold the value,
+// nothing is copied and it returns the required size and false, so the caller
+// can grow dst and retry; this is distinguishable from a miss because the
+// returned size is greater than len(dst).
+//
+// If the cache was created with a [Codec], the value must be decoded first and
+// GetInto is no longer allocation free.
+func (c *Cache) GetInto(dst, key []byte) (int, bool) {
 	atomic.AddUint64(&c.getCalls, 1)
 	s := c.shard(key)
-	k := string(key)
 
 	s.mu.Lock()
-	e, ok := s.items[k]
-	if ok {
-		s.moveToFront(e)
-		val := e.value
+	e, ok := s.items[string(key)]
+	if !ok {
 		s.mu.Unlock()
-		if dst == nil {
-			return append([]byte(nil), val...)
+		atomic.AddUint64(&c.misses, 1)
+		return 0, false
+	}
+	c.touch(s, e)
+	if c.codec == nil {
+		defer s.mu.Unlock()
+		if len(e.value.buf) > len(dst) {
+			return len(e.value.buf), false
 		}
-		return append(dst[:0], val...)
+		return copy(dst, e.value.buf), true
 	}
+
+	v := e.value
+	v.acquire()
 	s.mu.Unlock()
+	val, ok := c.decode(v.buf)
+	v.release()
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return 0, false
+	}
+	if len(val) > len(dst) {
+		return len(val), false
+	}
+	return copy(dst, val), true
+}
 
-	atomic.AddUint64(&c.misses, 1)
-	if dst == nil {
-		return nil
+// decode returns the caller-visible form of a stored value. Values that fail
+// to decode are reported as missing.
+func (c *Cache) decode(val []byte) ([]byte, bool) {
+	decoded, err := c.decodeErr(val)
+	return decoded, err == nil
+}
+
+// decodeErr is like decode, but returns why a value failed to decode.
+func (c *Cache) decodeErr(val []byte) ([]byte, error) {
+	if c.codec == nil {
+		return val, nil
+	}
+	decoded, err := c.codec.Decompress(val)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecode, err)
 	}
-	return dst[:0]
+	return decoded, nil
 }
 
 // GetBig is an alias for Get (compatibility).
@@ -171,40 +464,122 @@ func (c *Cache) Set(key, value []byte) {
 	atomic.AddUint64(&c.setCalls, 1)
 	s := c.shard(key)
 	k := string(key)
-	v := append([]byte(nil), value...)
-	entrySize := len(k) + len(v)
+	v := c.newValue(value)
+	entrySize := len(k) + len(v.buf)
 
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	c.set(s, k, v, entrySize)
+	s.mu.Unlock()
+
+	if c.shared {
+		c.evictShared()
+	}
+}
 
+// set inserts or updates an entry in s. Must be called with s.mu held.
+func (c *Cache) set(s *byteShard, k string, v *byteValue, entrySize int) {
 	// Entry too large for shard
 	if int64(entrySize) > s.maxSize {
+		v.release()
 		return
 	}
 
 	// Update existing
 	if e, ok := s.items[k]; ok {
-		s.currentSize -= int64(e.size)
+		delta := int64(entrySize - e.size)
+		s.currentSize += delta
+		atomic.AddInt64(&c.bytes, delta)
+		e.value.release()
 		e.value = v
 		e.size = entrySize
-		s.currentSize += int64(entrySize)
-		s.moveToFront(e)
+		c.touch(s, e)
+		// A larger value may push the shard over capacity. e is now the
+		// front entry and fits on its own, so it is never evicted here.
+		for !c.shared && s.currentSize > s.maxSize && s.tail != e {
+			c.remove(s, s.tail)
+		}
 		return
 	}
 
-	// Evict until we have space
-	for s.currentSize+int64(entrySize) > s.maxSize && s.tail != nil {
-		old := s.tail
-		s.unlink(old)
-		s.currentSize -= int64(old.size)
-		delete(s.items, old.key)
+	// Evict until we have space. In shared mode eviction is done globally
+	// after the shard lock is released.
+	for !c.shared && s.currentSize+int64(entrySize) > s.maxSize && s.tail != nil {
+		c.remove(s, s.tail)
 	}
 
 	// Insert new entry
 	e := &byteEntry{key: k, value: v, size: entrySize}
 	s.items[k] = e
+	s.peak = max(s.peak, len(s.items))
 	s.pushFront(e)
 	s.currentSize += int64(entrySize)
+	atomic.AddInt64(&c.bytes, int64(entrySize))
+	atomic.AddInt64(&c.entries, 1)
+	if c.shared {
+		e.seq = atomic.AddUint64(&c.clock, 1)
+	}
+}
+
+// touch marks e as the most recently used entry of s. Must be called with s.mu
+// held.
+func (c *Cache) touch(s *byteShard, e *byteEntry) {
+	s.moveToFront(e)
+	if e.hits < math.MaxUint32 {
+		e.hits++
+	}
+	if c.shared {
+		e.seq = atomic.AddUint64(&c.clock, 1)
+	}
+}
+
+// remove deletes e from s. Must be called with s.mu held.
+func (c *Cache) remove(s *byteShard, e *byteEntry) {
+	s.unlink(e)
+	s.currentSize -= int64(e.size)
+	atomic.AddInt64(&c.bytes, -int64(e.size))
+	atomic.AddInt64(&c.entries, -1)
+	delete(s.items, e.key)
+	e.value.release()
+}
+
+// evictShared evicts approximately globally-oldest entries until the total
+// size is within maxBytes. Only one shard lock is held at a time.
+func (c *Cache) evictShared() {
+	for atomic.LoadInt64(&c.bytes) > c.maxBytes {
+		victim := c.oldestShard()
+		if victim == nil {
+			return
+		}
+		victim.mu.Lock()
+		if victim.tail != nil {
+			c.remove(victim, victim.tail)
+		}
+		victim.mu.Unlock()
+	}
+}
+
+// oldestShard returns the shard whose LRU tail is the oldest among a sample of
+// [sharedEvictionSamples] non-empty shards, or nil if every shard is empty.
+func (c *Cache) oldestShard() *byteShard {
+	var (
+		start   = atomic.AddUint64(&c.evictCursor, sharedEvictionSamples)
+		victim  *byteShard
+		oldest  uint64
+		sampled int
+	)
+	for i := uint64(0); i < uint64(len(c.shards)) && sampled < sharedEvictionSamples; i++ {
+		s := c.shards[int(start+i)&c.mask]
+		s.mu.RLock()
+		if s.tail != nil {
+			if victim == nil || s.tail.seq < oldest {
+				victim = s
+				oldest = s.tail.seq
+			}
+			sampled++
+		}
+		s.mu.RUnlock()
+	}
+	return victim
 }
 
 // SetBig is an alias for Set (compatibility).
@@ -212,7 +587,46 @@ func (c *Cache) SetBig(key, value []byte) {
 	c.Set(key, value)
 }
 
-// UpdateStats populates the provided stats struct.
+// Cap returns the maximum number of bytes the cache may hold.
+func (c *Cache) Cap() int {
+	return int(c.maxBytes)
+}
+
+// Size returns the number of bytes currently held by the cache, counting both
+// keys and stored values.
+func (c *Cache) Size() int {
+	return int(atomic.LoadInt64(&c.bytes))
+}
+
+// Bytes returns the number of bytes currently held by the cache, like Size.
+func (c *Cache) Bytes() int64 {
+	return atomic.LoadInt64(&c.bytes)
+}
+
+// MaxBytes returns the maximum number of bytes the cache may hold, like Cap.
+func (c *Cache) MaxBytes() int64 {
+	return c.maxBytes
+}
+
+// ApproxLen returns the number of entries in the cache without locking any
+// shard. It is approximate: while other goroutines are mutating the cache it
+// may be momentarily off by the number of mutations in flight, and it is exact
+// once they have returned. Use [Cache.UpdateStats] for a count consistent with
+// each shard's contents.
+func (c *Cache) ApproxLen() int {
+	return int(atomic.LoadInt64(&c.entries))
+}
+
+// ApproxBytes returns the number of bytes held by the cache, counting keys and
+// stored values, without locking any shard. Like [Cache.ApproxLen] it may drift
+// momentarily under concurrent mutation.
+func (c *Cache) ApproxBytes() int64 {
+	return atomic.LoadInt64(&c.bytes)
+}
+
+// UpdateStats populates the provided stats struct. It read-locks every shard
+// in turn; prefer [Cache.ApproxLen] and [Cache.ApproxBytes] for frequent
+// polling.
 func (c *Cache) UpdateStats(s *Stats) {
 	if s == nil {
 		return
@@ -246,6 +660,18 @@ func (s *byteShard) pushFront(e *byteEntry) {
 	}
 }
 
+func (s *byteShard) pushBack(e *byteEntry) {
+	e.prev = s.tail
+	e.next = nil
+	if s.tail != nil {
+		s.tail.next = e
+	}
+	s.tail = e
+	if s.head == nil {
+		s.head = e
+	}
+}
+
 func (s *byteShard) unlink(e *byteEntry) {
 	if e.prev != nil {
 		e.prev.next = e.next
@@ -267,13 +693,3 @@ func (s *byteShard) moveToFront(e *byteEntry) {
 	s.unlink(e)
 	s.pushFront(e)
 }
-
-// SaveToFileConcurrent is a no-op for compatibility with fastcache API.
-func (c *Cache) SaveToFileConcurrent(filePath string, concurrency int) error {
-	return nil
-}
-
-// LoadFromFile is a no-op for compatibility with fastcache API.
-func (c *Cache) LoadFromFile(filePath string) error {
-	return nil
-}
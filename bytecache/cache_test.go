@@ -0,0 +1,412 @@
+// Copyright (C) 2019-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/cache"
+)
+
+func TestGetInto(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 20)
+	c.Set([]byte("key"), []byte("value"))
+
+	buf := make([]byte, 8)
+	n, ok := c.GetInto(buf, []byte("key"))
+	require.True(ok)
+	require.Equal(5, n)
+	require.Equal([]byte("value"), buf[:n])
+
+	n, ok = c.GetInto(buf, []byte("missing"))
+	require.False(ok)
+	require.Zero(n)
+
+	short := make([]byte, 2)
+	n, ok = c.GetInto(short, []byte("key"))
+	require.False(ok)
+	require.Equal(5, n)
+	require.Equal([]byte{0, 0}, short)
+}
+
+func TestGetIntoAllocs(t *testing.T) {
+	c := New(1 << 20)
+	key := []byte("key")
+	c.Set(key, []byte("value"))
+	buf := make([]byte, 8)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = c.GetInto(buf, key)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestHasAllocs(t *testing.T) {
+	c := New(1 << 20)
+	// Keys longer than 32 bytes can't be converted to a string on the stack.
+	key := bytes.Repeat([]byte("k"), 64)
+	c.Set(key, []byte("value"))
+	missing := bytes.Repeat([]byte("m"), 64)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = c.Has(key)
+		_ = c.Has(missing)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestHasDoesNotRetainKey(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 20)
+	key := []byte("key-1")
+	require.False(c.Has(key))
+	c.Set(key, []byte("value"))
+	require.True(c.Has(key))
+
+	// Reusing the buffer must not affect the stored key.
+	key[len(key)-1] = '2'
+	require.False(c.Has(key))
+	require.True(c.Has([]byte("key-1")))
+	require.False(c.Has([]byte("key-")))
+	require.False(c.Has([]byte("key-10")))
+
+	c.Del([]byte("key-1"))
+	require.False(c.Has([]byte("key-1")))
+}
+
+// sameShardKey returns a distinct key for each i that always maps to shard 0.
+func sameShardKey(i int) []byte {
+	return []byte{byte(i), byte(i), byte(i >> 8), byte(i >> 8)}
+}
+
+func TestSharedBorrowsCapacity(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		maxBytes  = numShards * 100
+		valueSize = 60
+		entrySize = 4 + valueSize
+		numKeys   = 100
+	)
+	value := make([]byte, valueSize)
+
+	equal := New(maxBytes)
+	shared := NewShared(maxBytes)
+	for i := 0; i < numKeys; i++ {
+		equal.Set(sameShardKey(i), value)
+		shared.Set(sameShardKey(i), value)
+	}
+
+	var stats Stats
+	equal.UpdateStats(&stats)
+	require.Equal(uint64(1), stats.EntriesCount)
+
+	shared.UpdateStats(&stats)
+	require.Equal(uint64(numKeys), stats.EntriesCount)
+	require.Equal(uint64(numKeys*entrySize), stats.BytesSize)
+	for i := 0; i < numKeys; i++ {
+		require.True(shared.Has(sameShardKey(i)))
+	}
+}
+
+func TestSharedEvictsGloballyOldest(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		maxBytes  = numShards * 100
+		valueSize = 60
+		entrySize = 4 + valueSize
+		capacity  = maxBytes / entrySize
+	)
+	value := make([]byte, valueSize)
+
+	c := NewShared(maxBytes)
+	for i := 0; i < capacity; i++ {
+		c.Set(sameShardKey(i), value)
+	}
+	// Promote the first key so the second becomes the oldest.
+	require.True(c.Has(sameShardKey(0)))
+	_ = c.Get(nil, sameShardKey(0))
+
+	c.Set(sameShardKey(capacity), value)
+
+	require.True(c.Has(sameShardKey(0)))
+	require.False(c.Has(sameShardKey(1)))
+	require.True(c.Has(sameShardKey(capacity)))
+
+	var stats Stats
+	c.UpdateStats(&stats)
+	require.LessOrEqual(stats.BytesSize, uint64(maxBytes))
+	require.Equal(uint64(capacity), stats.EntriesCount)
+
+	c.Del(sameShardKey(0))
+	c.Reset()
+	require.Zero(c.bytes)
+}
+
+// fewShardsKey returns a distinct key for each i that maps to one of the first
+// 16 shards.
+func fewShardsKey(i int) []byte {
+	key := []byte{byte(i), byte(i >> 8), byte(i >> 16), 0}
+	key[3] = key[0] ^ key[1] ^ key[2] ^ byte(i%16)
+	return key
+}
+
+func BenchmarkSkewedHitRate(b *testing.B) {
+	const (
+		maxBytes  = 1 << 20
+		valueSize = 252
+		numKeys   = 4096
+	)
+	value := make([]byte, valueSize)
+
+	constructors := map[string]func(int) *Cache{
+		"equal":  New,
+		"shared": NewShared,
+	}
+	for name, newCache := range constructors {
+		b.Run(name, func(b *testing.B) {
+			c := newCache(maxBytes)
+			zipf := rand.NewZipf(rand.New(rand.NewSource(0)), 1.1, 1, numKeys-1)
+			var hits int
+			for i := 0; i < b.N; i++ {
+				key := fewShardsKey(int(zipf.Uint64()))
+				if _, ok := c.HasGet(nil, key); ok {
+					hits++
+					continue
+				}
+				c.Set(key, value)
+			}
+			b.ReportMetric(float64(hits)/float64(b.N), "hit-rate")
+		})
+	}
+}
+
+func BenchmarkWarmup(b *testing.B) {
+	const numEntries = 100_000
+	for _, hint := range []int{0, numEntries} {
+		b.Run(fmt.Sprintf("hint=%d", hint), func(b *testing.B) {
+			key := make([]byte, 8)
+			for i := 0; i < b.N; i++ {
+				c := NewWithExpectedEntries(64<<20, hint)
+				for j := 0; j < numEntries; j++ {
+					binary.BigEndian.PutUint64(key, uint64(j))
+					c.Set(key, key)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkRefillAfterReset measures refilling a cache after it was reset
+// without presizing, with the default presizing from the shard peaks, and
+// with an exact hint.
+func BenchmarkRefillAfterReset(b *testing.B) {
+	const numEntries = 100_000
+	key := make([]byte, 8)
+	fill := func(c *Cache) {
+		for j := 0; j < numEntries; j++ {
+			binary.BigEndian.PutUint64(key, uint64(j))
+			c.Set(key, key)
+		}
+	}
+	for _, bc := range []struct {
+		name  string
+		reset func(*Cache)
+	}{
+		{name: "unsized", reset: func(c *Cache) { c.ResetPresized(0) }},
+		{name: "peak", reset: (*Cache).Reset},
+		{name: "hint", reset: func(c *Cache) { c.ResetPresized(numEntries) }},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			c := New(64 << 20)
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				fill(c)
+				bc.reset(c)
+				b.StartTimer()
+				fill(c)
+			}
+		})
+	}
+}
+
+func TestTouch(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		maxBytes  = numShards * 16
+		valueSize = 4
+	)
+	value := make([]byte, valueSize)
+	c := New(maxBytes)
+	// Each shard has room for exactly two entries.
+	c.Set(sameShardKey(1), value)
+	c.Set(sameShardKey(2), value)
+	require.True(c.Touch(sameShardKey(1)))
+	require.False(c.Touch(sameShardKey(3)))
+
+	c.Set(sameShardKey(3), value)
+	require.True(c.Has(sameShardKey(1)))
+	require.False(c.Has(sameShardKey(2)))
+	require.True(c.Has(sameShardKey(3)))
+}
+
+func TestSizeReporter(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 20)
+	c.Set([]byte("key"), []byte("value"))
+	require.Equal(1<<20, c.Cap())
+	require.Equal(8, c.Size())
+
+	c.Del([]byte("key"))
+	require.Zero(c.Size())
+}
+
+func TestByteSized(t *testing.T) {
+	require := require.New(t)
+
+	var c cache.ByteSized = NewWithShards(100, 1)
+	require.Equal(int64(100), c.MaxBytes())
+	require.Zero(c.Bytes())
+
+	b := c.(*Cache)
+	b.Set([]byte("key"), []byte("value"))
+	require.Equal(int64(8), c.Bytes())
+	b.Set([]byte("key"), []byte("longer value"))
+	require.Equal(int64(15), c.Bytes())
+	b.Set([]byte("other"), bytes.Repeat([]byte{0}, 90))
+	require.Equal(int64(95), c.Bytes())
+	b.Del([]byte("other"))
+	require.Zero(c.Bytes())
+	b.Reset()
+	require.Zero(c.Bytes())
+}
+
+func TestResetShard(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 20)
+	// {1} and {1, 0} share shard 1, {2} lives in shard 2 and {3} in shard 3.
+	keys := [][]byte{{1}, {1, 0}, {2}, {3}}
+	for _, key := range keys {
+		c.Set(key, []byte("value"))
+	}
+
+	c.ResetShard([]byte{1, 1, 1})
+	require.False(c.Has(keys[0]))
+	require.False(c.Has(keys[1]))
+	require.True(c.Has(keys[2]))
+	require.True(c.Has(keys[3]))
+	require.Equal(2*(1+len("value")), c.Size())
+
+	c.ResetShards([][]byte{{2}, {2}, {3}})
+	require.Zero(c.Size())
+	require.False(c.Has(keys[2]))
+	require.False(c.Has(keys[3]))
+}
+
+func TestApproxCounters(t *testing.T) {
+	require := require.New(t)
+
+	// A small cache forces evictions from the shards.
+	c := New(64 * numShards)
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(w)))
+			key := make([]byte, 4)
+			for range 10000 {
+				binary.BigEndian.PutUint32(key, uint32(r.Intn(4096)))
+				switch r.Intn(10) {
+				case 0:
+					c.Del(key)
+				case 1:
+					c.ResetShard(key)
+				default:
+					c.Set(key, make([]byte, r.Intn(32)))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	var stats Stats
+	c.UpdateStats(&stats)
+	require.Equal(int(stats.EntriesCount), c.ApproxLen())
+	require.Equal(int64(stats.BytesSize), c.ApproxBytes())
+
+	c.Reset()
+	require.Zero(c.ApproxLen())
+	require.Zero(c.ApproxBytes())
+}
+
+func TestGetDel(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 20)
+	c.Set([]byte("key"), []byte("value"))
+
+	const callers = 8
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		hits [][]byte
+	)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, ok := c.GetDel(nil, []byte("key")); ok {
+				mu.Lock()
+				hits = append(hits, v)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal([][]byte{[]byte("value")}, hits)
+	require.False(c.Has([]byte("key")))
+	require.Zero(c.Size())
+
+	dst := make([]byte, 0, 8)
+	v, ok := c.GetDel(dst, []byte("key"))
+	require.False(ok)
+	require.Empty(v)
+}
+
+func TestResetTracksPeak(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 20)
+	for i := range 4 {
+		c.Set(sameShardKey(i+1), []byte("value"))
+	}
+	c.Del(sameShardKey(1))
+	s := c.shard(sameShardKey(1))
+	require.Equal(4, s.peak)
+
+	c.Reset()
+	require.Zero(c.ApproxLen())
+	require.Zero(s.peak)
+
+	c.Set(sameShardKey(1), []byte("value"))
+	require.Equal(1, s.peak)
+	c.ResetPresized(1 << 16)
+	require.Zero(c.ApproxLen())
+	require.False(c.Has(sameShardKey(1)))
+}
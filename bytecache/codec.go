@@ -0,0 +1,60 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"sync"
+)
+
+var _ Codec = (*FlateCodec)(nil)
+
+// Codec transforms values as they are stored in and read from the cache.
+type Codec interface {
+	// Compress returns the encoded form of src. The returned slice must not
+	// alias src.
+	Compress(src []byte) []byte
+	// Decompress returns the original value encoded by Compress.
+	Decompress(src []byte) ([]byte, error)
+}
+
+// FlateCodec is a [Codec] using DEFLATE from the standard library. It favors
+// ratio over speed; callers that need a faster codec can provide their own.
+type FlateCodec struct {
+	level   int
+	writers sync.Pool
+}
+
+// NewFlateCodec returns a DEFLATE codec with the given compression level, as
+// defined by the compress/flate package. An invalid level falls back to
+// [flate.DefaultCompression].
+func NewFlateCodec(level int) *FlateCodec {
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		level = flate.DefaultCompression
+	}
+	return &FlateCodec{level: level}
+}
+
+func (f *FlateCodec) Compress(src []byte) []byte {
+	var buf bytes.Buffer
+	w, _ := f.writers.Get().(*flate.Writer)
+	if w == nil {
+		// The level has been validated, so NewWriter can't fail.
+		w, _ = flate.NewWriter(&buf, f.level)
+	} else {
+		w.Reset(&buf)
+	}
+	_, _ = w.Write(src)
+	_ = w.Close()
+	f.writers.Put(w)
+	return buf.Bytes()
+}
+
+func (*FlateCodec) Decompress(src []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(src))
+	defer r.Close()
+	return io.ReadAll(r)
+}
@@ -0,0 +1,37 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"bytes"
+	"compress/flate"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressionRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	c := NewWithCompression(1<<20, NewFlateCodec(flate.BestSpeed))
+	key := []byte("response")
+	value := bytes.Repeat([]byte(`{"jsonrpc":"2.0","result":"0x0"}`), 64)
+	c.Set(key, value)
+
+	got, ok := c.HasGet(nil, key)
+	require.True(ok)
+	require.Equal(value, got)
+
+	buf := make([]byte, len(value))
+	n, ok := c.GetInto(buf, key)
+	require.True(ok)
+	require.Equal(value, buf[:n])
+
+	var stats Stats
+	c.UpdateStats(&stats)
+	require.Less(stats.BytesSize, uint64(len(key)+len(value)))
+	s := c.shard(key)
+	require.Equal(int64(stats.BytesSize), s.currentSize)
+	require.Equal(len(key)+len(s.items[string(key)].value.buf), int(stats.BytesSize))
+}
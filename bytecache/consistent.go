@@ -0,0 +1,46 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+// FNV-1a 64-bit parameters, inlined to keep shard selection allocation free.
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// NewConsistent creates a byte cache that assigns keys to shards with
+// ConsistentShard instead of the default selector. Lookups are slightly more
+// expensive, but the assignment of keys to shards is stable across processes
+// and only moves a minimal fraction of keys if the number of shards changes.
+// Options.Consistent selects the same with any other setting, the number of
+// shards in particular.
+func NewConsistent(maxBytes int) *Cache {
+	return NewWithOptions(Options{MaxBytes: maxBytes, Consistent: true})
+}
+
+// ConsistentShard maps key to a shard in [0, shards) using jump consistent
+// hashing over the 64-bit FNV-1a hash of the key. When the number of shards
+// grows from n to m, only about (m-n)/m of the keys move, all of them to the
+// new shards, whereas a modulo based selector generally moves most keys.
+// ConsistentShard returns 0 if shards <= 1.
+func ConsistentShard(key []byte, shards int) int {
+	h := uint64(fnvOffset64)
+	for _, b := range key {
+		h ^= uint64(b)
+		h *= fnvPrime64
+	}
+	return jumpHash(h, shards)
+}
+
+// jumpHash implements "A Fast, Minimal Memory, Consistent Hash Algorithm" by
+// Lamping and Veach.
+func jumpHash(key uint64, buckets int) int {
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(max(b, 0))
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func remapFraction(numKeys, from, to int, shard func(key []byte, shards int) int) float64 {
+	moved := 0
+	key := make([]byte, 8)
+	for i := 0; i < numKeys; i++ {
+		binary.BigEndian.PutUint64(key, uint64(i))
+		if shard(key, from) != shard(key, to) {
+			moved++
+		}
+	}
+	return float64(moved) / float64(numKeys)
+}
+
+func moduloShard(key []byte, shards int) int {
+	h := fnv.New64a()
+	_, _ = h.Write(key)
+	return int(h.Sum64() % uint64(shards))
+}
+
+func TestConsistentShardRemap(t *testing.T) {
+	require := require.New(t)
+
+	const numKeys = 100_000
+
+	// Doubling the number of shards moves half the keys, the minimum.
+	require.InDelta(0.5, remapFraction(numKeys, 128, 256, ConsistentShard), 0.01)
+
+	// Adding a single shard moves about 1/N of the keys, while a modulo based
+	// selector moves almost all of them.
+	require.InDelta(1.0/129, remapFraction(numKeys, 128, 129, ConsistentShard), 0.005)
+	require.Greater(remapFraction(numKeys, 128, 129, moduloShard), 0.9)
+}
+
+func TestConsistentShardRange(t *testing.T) {
+	require := require.New(t)
+
+	counts := make([]int, 256)
+	key := make([]byte, 8)
+	for i := 0; i < 256_000; i++ {
+		binary.BigEndian.PutUint64(key, uint64(i))
+		counts[ConsistentShard(key, len(counts))]++
+	}
+	for _, count := range counts {
+		require.InDelta(1000, count, 200)
+	}
+	require.Zero(ConsistentShard(key, 1))
+	require.Zero(ConsistentShard(key, 0))
+}
+
+func TestNewConsistent(t *testing.T) {
+	require := require.New(t)
+
+	c := NewConsistent(1 << 20)
+	for i := 0; i < 100; i++ {
+		key := []byte{byte(i)}
+		c.Set(key, key)
+	}
+	for i := 0; i < 100; i++ {
+		key := []byte{byte(i)}
+		v, ok := c.HasGet(nil, key)
+		require.True(ok)
+		require.Equal(key, v)
+	}
+	c.ResetShard([]byte{0})
+	require.False(c.Has([]byte{0}))
+}
+
+func TestConsistentCacheRemap(t *testing.T) {
+	require := require.New(t)
+
+	const numKeys = 10_000
+	var (
+		small = NewWithOptions(Options{MaxBytes: 1 << 24, Shards: 128, Consistent: true})
+		large = NewWithOptions(Options{MaxBytes: 1 << 24, Shards: 256, Consistent: true})
+		key   = make([]byte, 8)
+		moved int
+	)
+	require.Equal(128, small.Shards())
+	require.Equal(256, large.Shards())
+	for i := 0; i < numKeys; i++ {
+		binary.BigEndian.PutUint64(key, uint64(i))
+		small.Set(key, key)
+		large.Set(key, key)
+	}
+
+	shardOf := func(c *Cache, key []byte) int {
+		for i, s := range c.shards {
+			if _, ok := s.items[string(key)]; ok {
+				return i
+			}
+		}
+		return -1
+	}
+	for i := 0; i < numKeys; i++ {
+		binary.BigEndian.PutUint64(key, uint64(i))
+		from, to := shardOf(small, key), shardOf(large, key)
+		require.NotEqual(-1, from)
+		if from != to {
+			// Keys only move to the new shards.
+			require.GreaterOrEqual(to, 128)
+			moved++
+		}
+	}
+	require.InDelta(0.5, float64(moved)/numKeys, 0.03)
+}
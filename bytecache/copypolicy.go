@@ -0,0 +1,80 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+// CopyPolicy decides whether a [Cache] copies values on their way in and out.
+//
+// The policy applies to Set, SetBig, Get, GetBig, HasGet and GetChecked.
+// Methods whose name states their copying behavior, such as GetInto,
+// GetWritable, GetPooled, GetRef, SetNoCopy and GetNoCopy, behave the same
+// under every policy. Values of a cache created with a [Codec] or [Transform]
+// are encoded on Set and decoded on Get, which produces new buffers, so such
+// a cache always behaves as under AlwaysCopy.
+type CopyPolicy int
+
+const (
+	// AlwaysCopy, the default, copies values on Set and Get. The caller may
+	// modify the value passed to Set and the slice returned by Get, and the
+	// returned slice is dst, grown if needed, if dst is not nil.
+	AlwaysCopy CopyPolicy = iota
+	// NoCopy stores the caller's slice on Set and returns the cache's
+	// storage from Get, as SetNoCopy and GetNoCopy do. Neither may ever be
+	// modified, and dst is ignored. This avoids every copy, but a caller
+	// modifying a value it passed to Set or got from Get corrupts the cache.
+	NoCopy
+	// CopyOnWrite copies values on Set, so the caller may reuse the value it
+	// passed in, but Get returns the cache's storage, which must not be
+	// modified, and ignores dst. A caller that needs a buffer it can modify
+	// asks for one with GetWritable, paying for a copy only then.
+	CopyOnWrite
+)
+
+// String returns the name of the policy.
+func (p CopyPolicy) String() string {
+	switch p {
+	case AlwaysCopy:
+		return "AlwaysCopy"
+	case NoCopy:
+		return "NoCopy"
+	case CopyOnWrite:
+		return "CopyOnWrite"
+	default:
+		return "CopyPolicy(unknown)"
+	}
+}
+
+// NewWithCopyPolicy creates a byte cache like [New] that copies values
+// according to policy. Unknown policies behave like AlwaysCopy.
+//
+// The slices returned by Get under NoCopy and CopyOnWrite remain valid after
+// the entry is evicted or replaced, because the cache stops recycling a
+// buffer once it has been shared. Such a buffer is garbage collected instead.
+func NewWithCopyPolicy(maxBytes int, policy CopyPolicy) *Cache {
+	return NewWithOptions(Options{MaxBytes: maxBytes, CopyPolicy: policy})
+}
+
+// CopyPolicy returns the policy the cache copies values with.
+func (c *Cache) CopyPolicy() CopyPolicy {
+	return c.copyPolicy
+}
+
+// GetWritable returns a copy of the value for key, appended to dst[:0] like
+// HasGet under AlwaysCopy, and whether it exists. The caller may modify the
+// copy, whatever the cache's copy policy.
+func (c *Cache) GetWritable(dst, key []byte) ([]byte, bool) {
+	val, ok, _ := c.hasGet(dst, key, false)
+	return val, ok
+}
+
+// newValue returns the stored form of a value passed to Set.
+func (c *Cache) newValue(value []byte) *byteValue {
+	switch {
+	case c.codec != nil:
+		return newByteValue(c.codec.Compress(value), true)
+	case c.copyPolicy == NoCopy:
+		return newByteValue(value, false)
+	default:
+		return copyValue(value)
+	}
+}
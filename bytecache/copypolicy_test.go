@@ -0,0 +1,102 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyPolicyAliasing(t *testing.T) {
+	tests := []struct {
+		policy      CopyPolicy
+		storesInput bool
+		sharesGet   bool
+	}{
+		{policy: AlwaysCopy},
+		{policy: NoCopy, storesInput: true, sharesGet: true},
+		{policy: CopyOnWrite, sharesGet: true},
+	}
+	for _, test := range tests {
+		t.Run(test.policy.String(), func(t *testing.T) {
+			require := require.New(t)
+
+			c := NewWithCopyPolicy(1<<20, test.policy)
+			require.Equal(test.policy, c.CopyPolicy())
+			key := []byte("key")
+			value := []byte("value")
+			c.Set(key, value)
+
+			got := c.Get(nil, key)
+			require.Equal([]byte("value"), got)
+			require.Equal(test.storesInput, &got[0] == &value[0])
+			again := c.Get(make([]byte, 0, 8), key)
+			require.Equal(test.sharesGet, &got[0] == &again[0])
+
+			writable, ok := c.GetWritable(nil, key)
+			require.True(ok)
+			require.NotSame(&got[0], &writable[0])
+			writable[0] = 'V'
+			require.Equal([]byte("value"), c.Get(nil, key))
+
+			// A shared buffer stays valid once the entry is replaced.
+			c.Set(key, []byte("other"))
+			c.Set([]byte("yek"), []byte("xxxxx"))
+			require.Equal([]byte("value"), got)
+
+			_, ok = c.HasGet(nil, []byte("missing"))
+			require.False(ok)
+		})
+	}
+}
+
+func TestCopyPolicyConcurrent(t *testing.T) {
+	for _, policy := range []CopyPolicy{AlwaysCopy, NoCopy, CopyOnWrite} {
+		t.Run(policy.String(), func(t *testing.T) {
+			c := NewWithCopyPolicy(1<<10, policy)
+			key := []byte("key")
+			var wg sync.WaitGroup
+			for i := range 4 {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					for j := range 200 {
+						// Under NoCopy, a value must not be modified once set.
+						c.Set(key, []byte{byte(i), byte(j)})
+					}
+				}()
+				go func() {
+					defer wg.Done()
+					for range 200 {
+						if v, ok := c.HasGet(nil, key); ok {
+							require.Len(t, v, 2)
+						}
+						if v, ok := c.GetWritable(nil, key); ok {
+							v[0]++
+						}
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func TestCopyPolicyWithCodec(t *testing.T) {
+	require := require.New(t)
+
+	c := NewWithCopyPolicy(1<<20, NoCopy)
+	c.codec = NewFlateCodec(-1)
+	value := []byte("value")
+	c.Set([]byte("key"), value)
+	got := c.Get(nil, []byte("key"))
+	require.Equal(value, got)
+	require.NotSame(&value[0], &got[0])
+}
+
+func TestCopyPolicyUnknown(t *testing.T) {
+	require.Equal(t, AlwaysCopy, NewWithCopyPolicy(1<<20, CopyPolicy(-1)).CopyPolicy())
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// DumpLimit is the number of keys Dump lists before eliding the rest.
+const DumpLimit = 100
+
+var _ fmt.Stringer = (*Cache)(nil)
+
+// String summarizes the cache's shards, length, size, capacity, fill and
+// counters on one line, for logs. It locks no shard, so the length and size
+// are approximate as described by [Cache.ApproxLen].
+func (c *Cache) String() string {
+	bytes := c.ApproxBytes()
+	return fmt.Sprintf(
+		"bytecache.Cache{shards=%d len=%d bytes=%d cap=%d fill=%.1f%% gets=%d misses=%d}",
+		len(c.shards), c.ApproxLen(), bytes, c.maxBytes,
+		100*float64(bytes)/float64(c.maxBytes),
+		atomic.LoadUint64(&c.getCalls), atomic.LoadUint64(&c.misses),
+	)
+}
+
+// Dump returns String followed by the keys of every non-empty shard from most
+// to least recently used, one per line, listing at most DumpLimit keys in
+// total. Shards are read-locked one at a time, so the listing is not a
+// consistent snapshot of a cache that is being written to. It is meant for
+// debugging, not hot paths.
+func (c *Cache) Dump() string {
+	var b strings.Builder
+	b.WriteString(c.String())
+	listed := 0
+	for i, s := range c.shards {
+		s.mu.RLock()
+		for e := s.head; e != nil; e = e.next {
+			if listed == DumpLimit {
+				s.mu.RUnlock()
+				b.WriteString("\n...")
+				return b.String()
+			}
+			fmt.Fprintf(&b, "\nshard %d: %q", i, e.key)
+			listed++
+		}
+		s.mu.RUnlock()
+	}
+	return b.String()
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestString(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1000 * numShards)
+	c.Set([]byte("a"), []byte("123"))
+	c.Get(nil, []byte("a"))
+	c.Get(nil, []byte("b"))
+
+	require.Equal(
+		"bytecache.Cache{shards=256 len=1 bytes=4 cap=256000 fill=0.0% gets=2 misses=1}",
+		c.String(),
+	)
+}
+
+func TestDump(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1000 * numShards)
+	c.Set([]byte("a"), []byte("1"))
+	c.Set([]byte("b"), []byte("2"))
+
+	lines := strings.Split(c.Dump(), "\n")
+	require.Equal([]string{
+		c.String(),
+		`shard 97: "a"`,
+		`shard 98: "b"`,
+	}, lines)
+
+	for i := range 2 * DumpLimit {
+		c.Set(fmt.Appendf(nil, "key-%d", i), nil)
+	}
+	lines = strings.Split(c.Dump(), "\n")
+	require.Len(lines, DumpLimit+2)
+	require.Equal("...", lines[len(lines)-1])
+}
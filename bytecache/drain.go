@@ -0,0 +1,43 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+// Drain removes every entry from the cache and returns them as key-value
+// pairs. Shards are drained one at a time, each atomically, so entries set
+// concurrently in an already drained shard stay in the cache. Within each
+// shard, entries are returned from least to most recently used, so setting
+// them in order into another cache restores the recency order of the shard.
+// The returned keys and values are copies that the caller owns.
+func (c *Cache) Drain() [][2][]byte {
+	var entries [][2][]byte
+	for _, s := range c.shards {
+		entries = c.drainShard(s, entries)
+	}
+	return entries
+}
+
+// DrainShard atomically removes every entry of the shard that key maps to and
+// returns them as Drain does. Entries of other shards are left untouched.
+func (c *Cache) DrainShard(key []byte) [][2][]byte {
+	return c.drainShard(c.shard(key), nil)
+}
+
+// drainShard appends the entries of s to entries and clears s.
+func (c *Cache) drainShard(s *byteShard, entries [][2][]byte) [][2][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for e := s.tail; e != nil; e = e.prev {
+		value, ok := c.decode(e.value.buf)
+		if !ok {
+			continue
+		}
+		entries = append(entries, [2][]byte{
+			[]byte(e.key),
+			append([]byte(nil), value...),
+		})
+	}
+	c.clearShard(s, -1)
+	return entries
+}
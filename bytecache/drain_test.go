@@ -0,0 +1,60 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrain(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 20)
+	want := make(map[string]string)
+	for i := range 100 {
+		key, value := fmt.Sprintf("key-%d", i), fmt.Sprintf("value-%d", i)
+		c.Set([]byte(key), []byte(value))
+		want[key] = value
+	}
+
+	entries := c.Drain()
+	got := make(map[string]string)
+	for _, entry := range entries {
+		got[string(entry[0])] = string(entry[1])
+	}
+	require.Equal(want, got)
+	require.Zero(c.ApproxLen())
+	require.Zero(c.Size())
+
+	// Refilling the cache leaves the drained copies untouched.
+	for key := range want {
+		c.Set([]byte(key), []byte("overwritten"))
+	}
+	for _, entry := range entries {
+		require.Equal(want[string(entry[0])], string(entry[1]))
+	}
+}
+
+func TestDrainShard(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 20)
+	// {1} and {1, 0} share shard 1 while {2} lives in shard 2.
+	c.Set([]byte{1}, []byte("a"))
+	c.Set([]byte{1, 0}, []byte("b"))
+	c.Set([]byte{2}, []byte("c"))
+	_ = c.Get(nil, []byte{1})
+
+	entries := c.DrainShard([]byte{1, 1, 1})
+	require.Equal([][2][]byte{
+		{{1, 0}, []byte("b")},
+		{{1}, []byte("a")},
+	}, entries)
+	require.False(c.Has([]byte{1}))
+	require.True(c.Has([]byte{2}))
+	require.Equal(1, c.ApproxLen())
+}
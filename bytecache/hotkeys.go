@@ -0,0 +1,35 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import "github.com/luxfi/cache"
+
+// shardHotKeyCapacity is the number of keys tracked by each shard of a cache
+// created with NewWithHotKeys.
+const shardHotKeyCapacity = 16
+
+// NewWithHotKeys creates a byte cache that tracks its most accessed keys,
+// reported by HotKeys. Each shard samples one in every sampleRate of the
+// operations on its keys and tracks up to 16 keys, so a key accounting for a
+// large share of its shard's traffic is kept even if other shards are busier.
+// Only sampled accesses pay for converting their key to a string.
+func NewWithHotKeys(maxBytes, sampleRate int) *Cache {
+	return NewWithOptions(Options{MaxBytes: maxBytes, HotKeySampleRate: max(sampleRate, 1)})
+}
+
+// HotKeys returns up to n of the most accessed keys across all shards with
+// their estimated number of operations, most accessed first. It returns nil
+// unless the cache was created with NewWithHotKeys.
+func (c *Cache) HotKeys(n int) []cache.HotKey[string] {
+	var hot []cache.HotKey[string]
+	for _, s := range c.shards {
+		if s.hot != nil {
+			hot = append(hot, s.hot.Top(n)...)
+		}
+	}
+	if hot == nil {
+		return nil
+	}
+	return cache.TopHotKeys(hot, n)
+}
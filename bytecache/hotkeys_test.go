@@ -0,0 +1,32 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHotKeys(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(New(1 << 20).HotKeys(1))
+
+	c := NewWithHotKeys(1<<20, 2)
+	c.Set([]byte("hot"), []byte("value"))
+	key := make([]byte, 8)
+	for i := range 10000 {
+		_ = c.Get(nil, []byte("hot"))
+		binary.BigEndian.PutUint64(key, uint64(i))
+		c.Set(key, key)
+	}
+
+	top := c.HotKeys(1)
+	require.Len(top, 1)
+	require.Equal("hot", top[0].Key)
+	require.InDelta(10000, float64(top[0].Count), 100)
+	require.Len(c.HotKeys(3), 3)
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrInvariantViolated is wrapped by the errors returned by CheckInvariants.
+var ErrInvariantViolated = errors.New("cache invariant violated")
+
+// CheckInvariants verifies the consistency of the cache's internal structures,
+// returning an error describing the first violation found. Shards are checked
+// one at a time, so the totals across shards are only consistent if no other
+// goroutine writes to the cache meanwhile. It is meant for tests, such as fuzz
+// tests that check the cache after random sequences of operations, not for
+// production code paths.
+func (c *Cache) CheckInvariants() error {
+	var entries, bytes int64
+	for i, s := range c.shards {
+		s.mu.RLock()
+		err := c.checkShard(s)
+		entries += int64(len(s.items))
+		bytes += s.currentSize
+		s.mu.RUnlock()
+		if err != nil {
+			return fmt.Errorf("shard %d: %w", i, err)
+		}
+	}
+	if n := atomic.LoadInt64(&c.entries); n != entries {
+		return fmt.Errorf("%w: %d entries are counted but the shards hold %d", ErrInvariantViolated, n, entries)
+	}
+	if n := atomic.LoadInt64(&c.bytes); n != bytes {
+		return fmt.Errorf("%w: %d bytes are counted but the shards hold %d", ErrInvariantViolated, n, bytes)
+	}
+	if c.shared && bytes > c.maxBytes {
+		return fmt.Errorf("%w: %d bytes exceed the capacity of %d", ErrInvariantViolated, bytes, c.maxBytes)
+	}
+	return nil
+}
+
+// checkShard verifies the consistency of s. Must be called with s.mu held.
+func (c *Cache) checkShard(s *byteShard) error {
+	if s.head != nil && s.head.prev != nil {
+		return fmt.Errorf("%w: head has a previous entry", ErrInvariantViolated)
+	}
+	if s.tail != nil && s.tail.next != nil {
+		return fmt.Errorf("%w: tail has a next entry", ErrInvariantViolated)
+	}
+	if (s.head == nil) != (s.tail == nil) {
+		return fmt.Errorf("%w: only one of head and tail is set", ErrInvariantViolated)
+	}
+
+	var (
+		n    int
+		size int64
+		last *byteEntry
+	)
+	for e := s.head; e != nil; e = e.next {
+		if n++; n > len(s.items) {
+			return fmt.Errorf("%w: list is longer than the %d entries of the map", ErrInvariantViolated, len(s.items))
+		}
+		if e.prev != last {
+			return fmt.Errorf("%w: entry %q is not linked back to its predecessor", ErrInvariantViolated, e.key)
+		}
+		if s.items[e.key] != e {
+			return fmt.Errorf("%w: map entry of key %q is not its list entry", ErrInvariantViolated, e.key)
+		}
+		if e.value == nil || atomic.LoadInt32(&e.value.refs) < 1 {
+			return fmt.Errorf("%w: value of key %q has been released", ErrInvariantViolated, e.key)
+		}
+		if want := len(e.key) + len(e.value.buf); e.size != want {
+			return fmt.Errorf("%w: key %q has size %d, want %d", ErrInvariantViolated, e.key, e.size, want)
+		}
+		size += int64(e.size)
+		last = e
+	}
+	if last != s.tail {
+		return fmt.Errorf("%w: list doesn't end at the tail", ErrInvariantViolated)
+	}
+	if n != len(s.items) {
+		return fmt.Errorf("%w: list holds %d entries but the map holds %d", ErrInvariantViolated, n, len(s.items))
+	}
+	if size != s.currentSize {
+		return fmt.Errorf("%w: entries total %d bytes but the size is %d", ErrInvariantViolated, size, s.currentSize)
+	}
+	if !c.shared && s.currentSize > s.maxSize {
+		return fmt.Errorf("%w: %d bytes exceed the capacity of %d", ErrInvariantViolated, s.currentSize, s.maxSize)
+	}
+	if s.peak < len(s.items) {
+		return fmt.Errorf("%w: peak of %d entries is below the %d held", ErrInvariantViolated, s.peak, len(s.items))
+	}
+	return nil
+}
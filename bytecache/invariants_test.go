@@ -0,0 +1,65 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func FuzzInvariants(f *testing.F) {
+	f.Add([]byte{0, 1, 0, 2, 1, 1, 2, 2, 0, 3})
+	f.Add([]byte{0, 15, 0, 31, 0, 47, 5, 7, 3, 15, 0, 63, 4, 1})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		caches := map[string]*Cache{
+			"default":    New(1 << 10),
+			"shared":     NewShared(1 << 10),
+			"sharded":    NewWithShards(256, 4),
+			"compressed": NewWithCompression(1<<10, NewFlateCodec(1)),
+		}
+		for name, c := range caches {
+			var dst []byte
+			for i := 0; i+1 < len(ops); i += 2 {
+				arg := ops[i+1]
+				key := []byte{arg % 32}
+				switch ops[i] % 8 {
+				case 0:
+					c.Set(key, bytes.Repeat([]byte{arg}, int(arg)))
+				case 1:
+					dst = c.Get(dst[:0], key)
+				case 2:
+					c.Del(key)
+				case 3:
+					dst, _ = c.GetDel(dst[:0], key)
+				case 4:
+					c.Touch(key)
+				case 5:
+					c.ResetShard(key)
+				case 6:
+					c.Reset()
+				case 7:
+					c.DrainShard(key)
+				}
+				require.NoError(t, c.CheckInvariants(), "%s after op %d", name, i/2)
+			}
+		}
+	})
+}
+
+func TestCheckInvariantsDetectsCorruption(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 10)
+	c.Set([]byte("a"), []byte("1"))
+	c.Set([]byte("b"), []byte("2"))
+	require.NoError(c.CheckInvariants())
+
+	s := c.shard([]byte("a"))
+	s.mu.Lock()
+	s.currentSize++
+	s.mu.Unlock()
+	require.ErrorIs(c.CheckInvariants(), ErrInvariantViolated)
+}
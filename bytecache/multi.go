@@ -0,0 +1,69 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// GetMultiInto looks up every key, storing the value of keys[i] in dsts[i] and
+// whether it was found in found[i]. Each value is appended to dsts[i][:0], so
+// a destination with enough capacity is reused and a smaller one is replaced
+// by a grown copy, as with append; on a miss dsts[i] is truncated to zero
+// length. It panics if keys and dsts differ in length.
+//
+// Keys are grouped by shard, so each shard is locked once however many of
+// the keys map to it, and apart from the returned slice and a scratch slice
+// of the same length no memory is allocated when the destinations are large
+// enough. If the cache was created with a [Codec], values are instead looked
+// up and decoded one at a time, as by HasGet.
+func (c *Cache) GetMultiInto(keys [][]byte, dsts [][]byte) []bool {
+	if len(keys) != len(dsts) {
+		panic("bytecache: GetMultiInto called with mismatched keys and dsts")
+	}
+	found := make([]bool, len(keys))
+	if c.codec != nil {
+		for i, key := range keys {
+			dsts[i], found[i] = c.HasGet(dsts[i][:0], key)
+		}
+		return found
+	}
+
+	// Each element packs the shard of a key above its index, so sorting
+	// groups the keys by shard without allocating a comparison closure.
+	order := make([]uint64, len(keys))
+	for i, key := range keys {
+		order[i] = uint64(c.shardIndex(key))<<32 | uint64(i)
+	}
+	slices.Sort(order)
+
+	atomic.AddUint64(&c.getCalls, uint64(len(keys)))
+	var misses uint64
+	for start := 0; start < len(order); {
+		s := c.shards[order[start]>>32]
+		end := start
+		s.mu.Lock()
+		for ; end < len(order) && c.shards[order[end]>>32] == s; end++ {
+			i := uint32(order[end])
+			key := keys[i]
+			if s.hot != nil && s.hot.Sampled() {
+				s.hot.Add(string(key))
+			}
+			e, ok := s.items[string(key)]
+			if !ok {
+				dsts[i] = dsts[i][:0]
+				misses++
+				continue
+			}
+			c.touch(s, e)
+			dsts[i] = append(dsts[i][:0], e.value.buf...)
+			found[i] = true
+		}
+		s.mu.Unlock()
+		start = end
+	}
+	atomic.AddUint64(&c.misses, misses)
+	return found
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMultiInto(t *testing.T) {
+	caches := map[string]*Cache{
+		"default":     New(1 << 20),
+		"compression": NewWithCompression(1<<20, NewFlateCodec(1)),
+		"consistent":  NewConsistent(1 << 20),
+	}
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			var keys, dsts [][]byte
+			for i := range 100 {
+				key := fmt.Appendf(nil, "key-%d", i)
+				if i%3 != 0 {
+					c.Set(key, fmt.Appendf(nil, "value-%d", i))
+				}
+				keys = append(keys, key)
+				dsts = append(dsts, make([]byte, 3, 16))
+			}
+			reused := &dsts[1][:1][0]
+
+			found := c.GetMultiInto(keys, dsts)
+			require.Len(found, len(keys))
+			for i := range keys {
+				require.Equal(i%3 != 0, found[i])
+				if found[i] {
+					require.Equal(fmt.Appendf(nil, "value-%d", i), dsts[i])
+				} else {
+					require.Empty(dsts[i])
+				}
+			}
+			require.Same(reused, &dsts[1][0])
+
+			var stats Stats
+			c.UpdateStats(&stats)
+			require.Equal(uint64(100), stats.GetCalls)
+			require.Equal(uint64(34), stats.Misses)
+		})
+	}
+}
+
+func TestGetMultiIntoMismatch(t *testing.T) {
+	require.Panics(t, func() {
+		New(1<<20).GetMultiInto(make([][]byte, 2), make([][]byte, 1))
+	})
+}
+
+func TestGetMultiIntoAllocs(t *testing.T) {
+	c := New(1 << 20)
+	keys := make([][]byte, 64)
+	dsts := make([][]byte, 64)
+	for i := range keys {
+		keys[i] = fmt.Appendf(nil, "key-%d", i)
+		dsts[i] = make([]byte, 0, 64)
+		c.Set(keys[i], make([]byte, 32))
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		c.GetMultiInto(keys, dsts)
+	})
+	require.Equal(t, 2.0, allocs)
+}
+
+func BenchmarkGetMultiInto(b *testing.B) {
+	c := New(1 << 24)
+	keys := make([][]byte, 256)
+	dsts := make([][]byte, 256)
+	for i := range keys {
+		keys[i] = fmt.Appendf(nil, "key-%d", i)
+		dsts[i] = make([]byte, 0, 256)
+		c.Set(keys[i], make([]byte, 128))
+	}
+
+	b.Run("Get", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j, key := range keys {
+				dsts[j] = c.Get(dsts[j][:0], key)
+			}
+		}
+	})
+	b.Run("GetMultiInto", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			c.GetMultiInto(keys, dsts)
+		}
+	})
+}
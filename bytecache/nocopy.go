@@ -0,0 +1,67 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import "sync/atomic"
+
+// SetNoCopy stores value under key without copying it.
+//
+// WARNING: this is unsafe unless value is immutable. The cache keeps a
+// reference to value, so the caller must never modify it after the call, and
+// readers using GetNoCopy receive value itself. The cache never recycles the
+// buffer. If the cache was created with a [Codec], value is compressed into a
+// new buffer and SetNoCopy behaves like Set.
+func (c *Cache) SetNoCopy(key, value []byte) {
+	atomic.AddUint64(&c.setCalls, 1)
+	s := c.shard(key)
+	k := string(key)
+	var v *byteValue
+	if c.codec == nil {
+		v = newByteValue(value, false)
+	} else {
+		v = newByteValue(c.codec.Compress(value), true)
+	}
+	entrySize := len(k) + len(v.buf)
+
+	s.mu.Lock()
+	c.set(s, k, v, entrySize)
+	s.mu.Unlock()
+
+	if c.shared {
+		c.evictShared()
+	}
+}
+
+// GetNoCopy returns the value for key without copying it.
+//
+// WARNING: the returned slice is the cache's own storage and must never be
+// modified. It remains valid after the entry is evicted or replaced, because
+// the cache stops recycling a buffer once it has been returned by GetNoCopy.
+// If the cache was created with a [Codec], the value is decoded into a new
+// buffer instead.
+func (c *Cache) GetNoCopy(key []byte) ([]byte, bool) {
+	atomic.AddUint64(&c.getCalls, 1)
+	s := c.shard(key)
+
+	s.mu.Lock()
+	e, ok := s.items[string(key)]
+	if !ok {
+		s.mu.Unlock()
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	c.touch(s, e)
+	v := e.value
+	// The reference is never released, which keeps the buffer from being
+	// recycled while the caller may still be reading it.
+	v.acquire()
+	s.mu.Unlock()
+
+	val, ok := c.decode(v.buf)
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	return val, true
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoCopy(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 20)
+	key := []byte("key")
+	value := []byte("value")
+	c.SetNoCopy(key, value)
+
+	got, ok := c.GetNoCopy(key)
+	require.True(ok)
+	require.Equal(value, got)
+	require.Same(&value[0], &got[0])
+
+	// A buffer returned by GetNoCopy is never recycled, even if it was
+	// copied in by Set.
+	c.Set(key, []byte("owned"))
+	got, ok = c.GetNoCopy(key)
+	require.True(ok)
+	c.Del(key)
+	c.Set([]byte("other"), []byte("xxxxx"))
+	require.Equal([]byte("owned"), got)
+
+	_, ok = c.GetNoCopy([]byte("missing"))
+	require.False(ok)
+}
+
+func TestNoCopyWithCodec(t *testing.T) {
+	require := require.New(t)
+
+	c := NewWithCompression(1<<20, NewFlateCodec(-1))
+	value := bytes.Repeat([]byte("abc"), 100)
+	c.SetNoCopy([]byte("key"), value)
+	got, ok := c.GetNoCopy([]byte("key"))
+	require.True(ok)
+	require.Equal(value, got)
+}
+
+func TestNoCopyConcurrent(t *testing.T) {
+	const (
+		writers = 4
+		readers = 4
+		rounds  = 1000
+	)
+	c := New(numShards * 64)
+	key := []byte("key")
+
+	var (
+		wg         sync.WaitGroup
+		mismatches int
+		lock       sync.Mutex
+	)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				// Each value is immutable once stored, per the contract.
+				c.SetNoCopy(key, []byte(fmt.Sprintf("value-%d-%d", w, i)))
+			}
+		}()
+	}
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				v, ok := c.GetNoCopy(key)
+				if ok && !bytes.HasPrefix(v, []byte("value-")) {
+					lock.Lock()
+					mismatches++
+					lock.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	require.Zero(t, mismatches)
+}
+
+func BenchmarkNoCopy(b *testing.B) {
+	c := New(1 << 24)
+	key := []byte("key")
+	value := make([]byte, 1024)
+
+	b.Run("Set", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			c.Set(key, value)
+		}
+	})
+	b.Run("SetNoCopy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			c.SetNoCopy(key, value)
+		}
+	})
+	b.Run("HasGet", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = c.HasGet(nil, key)
+		}
+	})
+	b.Run("GetNoCopy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = c.GetNoCopy(key)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import "github.com/luxfi/cache"
+
+// Options configures a Cache created by NewWithOptions. Every setting of the
+// other constructors has a field here, so that they can be combined. The zero
+// value of a field keeps the behavior of New.
+type Options struct {
+	// MaxBytes is the maximum total size of the keys and values held.
+	MaxBytes int
+	// Shards is the number of shards, rounded as by NewWithShards. Zero
+	// selects the 256 shards of New.
+	Shards int
+	// AutoShards picks the number of shards as NewAuto does, overriding
+	// Shards.
+	AutoShards bool
+	// Consistent assigns keys to shards with ConsistentShard, as
+	// NewConsistent does, so that changing Shards from one run to the next
+	// only moves a minimal fraction of the keys.
+	Consistent bool
+	// ExpectedEntries pre-sizes the shard maps, as NewWithExpectedEntries
+	// does.
+	ExpectedEntries int
+	// Shared makes the shards share a single budget, as described by
+	// NewShared.
+	Shared bool
+	// Codec compresses values, as by NewWithCompression.
+	Codec Codec
+	// Transform encodes values, as by NewWithTransform. If Codec is set too,
+	// values are compressed before they are transformed.
+	Transform Transform
+	// CopyPolicy decides whether values are copied, as by NewWithCopyPolicy.
+	CopyPolicy CopyPolicy
+	// HotKeySampleRate, if positive, tracks the most accessed keys, sampling
+	// one in every HotKeySampleRate operations, as NewWithHotKeys does.
+	HotKeySampleRate int
+}
+
+// NewWithOptions creates a byte cache configured by opts.
+func NewWithOptions(opts Options) *Cache {
+	shards := numShards
+	switch {
+	case opts.AutoShards:
+		shards = defaultAutoShards()
+	case opts.Shards != 0:
+		shards = roundShards(opts.Shards)
+	}
+	c := newCache(opts.MaxBytes, opts.ExpectedEntries, shards)
+	c.consistent = opts.Consistent
+	if opts.Shared {
+		c.shared = true
+		for _, s := range c.shards {
+			s.maxSize = c.maxBytes
+		}
+	}
+	c.codec = opts.Codec
+	if opts.Transform != nil {
+		c.codec = transformCodec{t: opts.Transform, codec: opts.Codec}
+	}
+	if opts.CopyPolicy == NoCopy || opts.CopyPolicy == CopyOnWrite {
+		c.copyPolicy = opts.CopyPolicy
+	}
+	if opts.HotKeySampleRate > 0 {
+		for _, s := range c.shards {
+			s.hot = cache.NewHotKeyTracker[string](shardHotKeyCapacity, opts.HotKeySampleRate)
+		}
+	}
+	return c
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"bytes"
+	"compress/flate"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWithOptionsCombines(t *testing.T) {
+	require := require.New(t)
+
+	aesgcm, err := NewAESGCM(bytes.Repeat([]byte{7}, 32))
+	require.NoError(err)
+	c := NewWithOptions(Options{
+		MaxBytes:         1 << 20,
+		Shards:           100,
+		Shared:           true,
+		Codec:            NewFlateCodec(flate.BestSpeed),
+		Transform:        aesgcm,
+		CopyPolicy:       CopyOnWrite,
+		HotKeySampleRate: 1,
+	})
+	require.Equal(128, c.Shards())
+	require.True(c.shared)
+	require.Equal(CopyOnWrite, c.CopyPolicy())
+
+	var (
+		key   = []byte("key")
+		value = bytes.Repeat([]byte("compressible "), 100)
+	)
+	c.Set(key, value)
+	stored := c.shard(key).items[string(key)].value.buf
+	require.Less(len(stored), len(value))
+	require.NotContains(string(stored), "compressible")
+	require.Equal(value, c.Get(nil, key))
+	require.Len(c.HotKeys(1), 1)
+	require.NoError(c.CheckInvariants())
+}
+
+func TestNewWithOptionsDefaults(t *testing.T) {
+	require := require.New(t)
+
+	c := NewWithOptions(Options{MaxBytes: 1 << 20})
+	require.Equal(numShards, c.Shards())
+	require.False(c.shared)
+	require.Nil(c.codec)
+	require.Equal(AlwaysCopy, c.CopyPolicy())
+	require.Nil(c.HotKeys(1))
+
+	auto := NewWithOptions(Options{MaxBytes: 1 << 20, AutoShards: true, Shards: 2})
+	require.Equal(defaultAutoShards(), auto.Shards())
+}
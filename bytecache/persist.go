@@ -0,0 +1,380 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"slices"
+	"sync/atomic"
+)
+
+const (
+	fileMagic = "LXBC"
+	// fileVersion 4 adds a flags word to the file header. Version 3 added
+	// each entry's access count to the record header, and version 2 a CRC-32
+	// of each record's key and value. Files of older versions are still
+	// loaded.
+	fileVersion         = 4
+	fileVersionNoFlags  = 3
+	fileVersionNoHits   = 2
+	fileVersionNoChecks = 1
+
+	// headerLen is the length of the magic and version that start every
+	// file, which are followed by flagsLen bytes of flags since version 4.
+	headerLen = len(fileMagic) + 4
+	flagsLen  = 4
+
+	// flagTransformed marks the files of a cache created with a [Transform],
+	// whose values are stored as encoded by it rather than decoded.
+	flagTransformed uint32 = 1 << 0
+	// knownFlags are the flags this version of the package understands.
+	knownFlags = flagTransformed
+
+	// recordHeaderLen is the key length, value length, checksum and access
+	// count of a record. Version 2 records omit the access count, and version
+	// 1 records the checksum too.
+	recordHeaderLen   = 16
+	recordHeaderLenV2 = 12
+	recordHeaderLenV1 = 8
+)
+
+// crcTable is the Castagnoli polynomial, which is hardware accelerated on
+// common architectures.
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ChecksumMode controls how loading handles records whose checksum does not
+// match their contents.
+type ChecksumMode int
+
+const (
+	// ChecksumStrict stops loading at the first mismatching record and
+	// returns [ErrChecksumMismatch].
+	ChecksumStrict ChecksumMode = iota
+	// ChecksumLenient skips mismatching records and keeps loading.
+	ChecksumLenient
+)
+
+var (
+	// ErrCorruptFile is returned when a cache file is not a valid cache file
+	// or contains malformed records. Callers may safely continue with a cold
+	// cache.
+	ErrCorruptFile = errors.New("corrupt cache file")
+	// ErrVersionMismatch is returned when a cache file was written with an
+	// unsupported format version.
+	ErrVersionMismatch = errors.New("cache file version mismatch")
+	// ErrShortBuffer is returned when a cache file ends in the middle of a
+	// record, typically due to a partial write.
+	ErrShortBuffer = errors.New("cache file truncated")
+	// ErrChecksumMismatch is returned when a record's contents do not match
+	// its checksum, typically due to a partial write or disk corruption.
+	ErrChecksumMismatch = errors.New("cache file checksum mismatch")
+	// ErrTransformMismatch is returned when a cache file holds values encoded
+	// by a [Transform] but the cache loading it has none.
+	ErrTransformMismatch = errors.New("cache file transform mismatch")
+)
+
+// SaveToFileConcurrent writes all cached entries to filePath.
+//
+// Values are written decoded, so that the file doesn't depend on the [Codec]
+// the cache was created with, except in a cache created with a [Transform]:
+// its values are written as encoded, so that values encrypted in memory stay
+// encrypted on disk, and can only be loaded by a cache with the same
+// Transform.
+//
+// The file is written to a temporary path and renamed into place so that a
+// crash during the write never leaves a partially written file at filePath.
+// concurrency is accepted for compatibility with the fastcache API and is
+// otherwise ignored.
+func (c *Cache) SaveToFileConcurrent(filePath string, concurrency int) error {
+	tmpPath := filePath + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+
+	if err := c.writeTo(f); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to close cache file: %w", err)
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename cache file: %w", err)
+	}
+	return nil
+}
+
+// LoadFromFile inserts the entries stored in filePath into the cache.
+//
+// A missing file is reported by an error satisfying errors.Is(err,
+// fs.ErrNotExist). Malformed contents are reported as [ErrCorruptFile] or
+// [ErrShortBuffer], and files written by an unsupported format version as
+// [ErrVersionMismatch]. A record whose checksum does not match is reported as
+// [ErrChecksumMismatch]. Files saved by a cache created with a [Transform] are
+// reported as [ErrTransformMismatch] if the cache has none, and a value its
+// Transform fails to decode as [ErrDecode]. Entries read before an error is
+// encountered remain in the cache.
+//
+// Entries are inserted from the most to the least accessed before the file
+// was saved, each as less recently used than those before it, and only while
+// they fit: once a shard is full, the remaining entries of that shard are
+// dropped rather than evicting more accessed ones. The cache thus keeps the
+// most valuable entries when the file holds more than its capacity. The file
+// is read in full before any entry is inserted. Files saved by older versions
+// don't record access counts and are loaded in the order they were saved, as
+// if by Set.
+func (c *Cache) LoadFromFile(filePath string) error {
+	_, err := c.LoadFromFileWithMode(filePath, ChecksumStrict)
+	return err
+}
+
+// LoadFromFileWithMode is like [Cache.LoadFromFile], with mode selecting how
+// records failing their checksum, or failing to decode, are handled. It
+// returns the number of records skipped, which is always zero under
+// [ChecksumStrict].
+//
+// A corrupted length field is indistinguishable from a valid one until the
+// record it frames fails its checksum, so lenient loading may skip what were
+// several records, or fail with [ErrCorruptFile] or [ErrShortBuffer] once the
+// framing can no longer be followed.
+func (c *Cache) LoadFromFileWithMode(filePath string, mode ChecksumMode) (int, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open cache file: %w", err)
+	}
+	defer f.Close()
+
+	return c.readFrom(f, mode)
+}
+
+func (c *Cache) writeTo(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	_, transformed := c.codec.(transformCodec)
+	var flags uint32
+	if transformed {
+		flags |= flagTransformed
+	}
+	var header [headerLen + flagsLen]byte
+	copy(header[:], fileMagic)
+	binary.BigEndian.PutUint32(header[len(fileMagic):], fileVersion)
+	binary.BigEndian.PutUint32(header[headerLen:], flags)
+	if _, err := bw.Write(header[:]); err != nil {
+		return fmt.Errorf("failed to write cache file header: %w", err)
+	}
+
+	var recordHeader [recordHeaderLen]byte
+	for _, s := range c.shards {
+		s.mu.RLock()
+		// Write from most to least recently used so that loading the file,
+		// which orders entries by access count, restores the recency order of
+		// entries accessed as often.
+		for e := s.head; e != nil; e = e.next {
+			value := e.value.buf
+			if !transformed {
+				var ok bool
+				if value, ok = c.decode(value); !ok {
+					continue
+				}
+			}
+			binary.BigEndian.PutUint32(recordHeader[:4], uint32(len(e.key)))
+			binary.BigEndian.PutUint32(recordHeader[4:8], uint32(len(value)))
+			checksum := crc32.Update(crc32.Checksum([]byte(e.key), crcTable), crcTable, value)
+			binary.BigEndian.PutUint32(recordHeader[8:12], checksum)
+			binary.BigEndian.PutUint32(recordHeader[12:], e.hits)
+			if _, err := bw.Write(recordHeader[:]); err != nil {
+				s.mu.RUnlock()
+				return fmt.Errorf("failed to write cache entry: %w", err)
+			}
+			if _, err := bw.WriteString(e.key); err != nil {
+				s.mu.RUnlock()
+				return fmt.Errorf("failed to write cache entry: %w", err)
+			}
+			if _, err := bw.Write(value); err != nil {
+				s.mu.RUnlock()
+				return fmt.Errorf("failed to write cache entry: %w", err)
+			}
+		}
+		s.mu.RUnlock()
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+func (c *Cache) readFrom(r io.Reader, mode ChecksumMode) (int, error) {
+	br := bufio.NewReader(r)
+
+	var header [headerLen]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return 0, readErr(err)
+	}
+	if string(header[:len(fileMagic)]) != fileMagic {
+		return 0, fmt.Errorf("%w: invalid magic %q", ErrCorruptFile, header[:len(fileMagic)])
+	}
+	version := binary.BigEndian.Uint32(header[len(fileMagic):])
+	var recordHeader [recordHeaderLen]byte
+	recordHeaderBuf := recordHeader[:]
+	switch version {
+	case fileVersion, fileVersionNoFlags:
+	case fileVersionNoHits:
+		recordHeaderBuf = recordHeader[:recordHeaderLenV2]
+	case fileVersionNoChecks:
+		recordHeaderBuf = recordHeader[:recordHeaderLenV1]
+	default:
+		return 0, fmt.Errorf("%w: %d != %d", ErrVersionMismatch, version, fileVersion)
+	}
+	var flags uint32
+	if version >= fileVersion {
+		var buf [flagsLen]byte
+		if _, err := io.ReadFull(br, buf[:]); err != nil {
+			return 0, readErr(err)
+		}
+		flags = binary.BigEndian.Uint32(buf[:])
+		if unknown := flags &^ knownFlags; unknown != 0 {
+			return 0, fmt.Errorf("%w: unsupported flags %#x", ErrVersionMismatch, unknown)
+		}
+	}
+	transformed := flags&flagTransformed != 0
+	if _, transform := c.codec.(transformCodec); transformed && !transform {
+		return 0, ErrTransformMismatch
+	}
+	var (
+		checked = version != fileVersionNoChecks
+		counted = version >= fileVersionNoFlags
+		skipped int
+		records []loadedRecord
+	)
+	// Records with access counts are only inserted once they have all been
+	// read, or reading fails.
+	defer func() {
+		c.insertLoaded(records, transformed)
+	}()
+	for {
+		_, err := io.ReadFull(br, recordHeaderBuf)
+		if err == io.EOF {
+			return skipped, nil
+		}
+		if err != nil {
+			return skipped, readErr(err)
+		}
+
+		keyLen := int64(binary.BigEndian.Uint32(recordHeader[:4]))
+		valueLen := int64(binary.BigEndian.Uint32(recordHeader[4:8]))
+		if keyLen+valueLen > c.maxBytes {
+			return skipped, fmt.Errorf("%w: entry of %d bytes exceeds cache size %d", ErrCorruptFile, keyLen+valueLen, c.maxBytes)
+		}
+
+		record := make([]byte, keyLen+valueLen)
+		if _, err := io.ReadFull(br, record); err != nil {
+			return skipped, readErr(err)
+		}
+		if checked {
+			want := binary.BigEndian.Uint32(recordHeader[8:12])
+			if got := crc32.Checksum(record, crcTable); got != want {
+				if mode == ChecksumStrict {
+					return skipped, fmt.Errorf("%w: record %x != %x", ErrChecksumMismatch, got, want)
+				}
+				skipped++
+				continue
+			}
+		}
+		if transformed {
+			// A value that fails to decode was encoded under another key or
+			// tampered with, and would be reported as missing once loaded.
+			if _, err := c.codec.Decompress(record[keyLen:]); err != nil {
+				if mode == ChecksumStrict {
+					return skipped, fmt.Errorf("%w: %w", ErrDecode, err)
+				}
+				skipped++
+				continue
+			}
+		}
+		if counted {
+			records = append(records, loadedRecord{
+				key:   record[:keyLen],
+				value: record[keyLen:],
+				hits:  binary.BigEndian.Uint32(recordHeader[12:]),
+			})
+		} else {
+			c.Set(record[:keyLen], record[keyLen:])
+		}
+	}
+}
+
+// loadedRecord is a record read from a cache file.
+type loadedRecord struct {
+	key, value []byte
+	hits       uint32
+}
+
+// insertLoaded inserts records as described by LoadFromFile. The values of
+// encoded records are stored as they are, rather than encoded again.
+func (c *Cache) insertLoaded(records []loadedRecord, encoded bool) {
+	slices.SortStableFunc(records, func(a, b loadedRecord) int {
+		return cmp.Compare(b.hits, a.hits)
+	})
+	for _, r := range records {
+		s := c.shards[c.shardIndex(r.key)]
+		k := string(r.key)
+		var v *byteValue
+		if encoded {
+			v = newByteValue(r.value, false)
+		} else {
+			v = c.newValue(r.value)
+		}
+		entrySize := len(k) + len(v.buf)
+
+		s.mu.Lock()
+		c.appendLoaded(s, k, v, entrySize, r.hits)
+		s.mu.Unlock()
+	}
+}
+
+// appendLoaded inserts a loaded entry into s as its least recently used
+// entry, if it fits without evicting any other and its key isn't already
+// cached. Must be called with s.mu held.
+func (c *Cache) appendLoaded(s *byteShard, k string, v *byteValue, entrySize int, hits uint32) {
+	_, exists := s.items[k]
+	full := s.currentSize+int64(entrySize) > s.maxSize
+	if c.shared {
+		full = atomic.LoadInt64(&c.bytes)+int64(entrySize) > c.maxBytes
+	}
+	if exists || full {
+		v.release()
+		return
+	}
+
+	// In shared mode, a zero sequence number ranks the entry as older than
+	// any accessed since, as it would be had it been inserted first.
+	e := &byteEntry{key: k, value: v, size: entrySize, hits: hits}
+	s.items[k] = e
+	s.peak = max(s.peak, len(s.items))
+	s.pushBack(e)
+	s.currentSize += int64(entrySize)
+	atomic.AddInt64(&c.bytes, int64(entrySize))
+	atomic.AddInt64(&c.entries, 1)
+}
+
+// readErr converts early EOFs into [ErrShortBuffer] and wraps all other read
+// errors.
+func readErr(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %w", ErrShortBuffer, err)
+	}
+	return fmt.Errorf("failed to read cache file: %w", err)
+}
@@ -0,0 +1,254 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "cache")
+	c := New(1 << 20)
+	for i := 0; i < 100; i++ {
+		c.Set([]byte{byte(i), 1}, []byte{byte(i), 2, 3})
+	}
+	require.NoError(c.SaveToFileConcurrent(path, 4))
+
+	loaded := New(1 << 20)
+	require.NoError(loaded.LoadFromFile(path))
+	for i := 0; i < 100; i++ {
+		v, ok := loaded.HasGet(nil, []byte{byte(i), 1})
+		require.True(ok)
+		require.Equal([]byte{byte(i), 2, 3}, v)
+	}
+}
+
+func TestLoadFromFileKeepsMostAccessed(t *testing.T) {
+	require := require.New(t)
+
+	// Key i is accessed i times, in an order unrelated to its key.
+	path := filepath.Join(t.TempDir(), "cache")
+	c := NewWithShards(1<<10, 1)
+	for _, i := range []byte{3, 7, 0, 9, 5, 1, 8, 2, 6, 4} {
+		c.Set([]byte{i}, []byte("value...."))
+		for range i {
+			require.True(c.Touch([]byte{i}))
+		}
+	}
+	require.NoError(c.SaveToFileConcurrent(path, 1))
+
+	// Each entry is 10 bytes, so the cache only holds 4 of them.
+	loaded := NewWithShards(40, 1)
+	require.NoError(loaded.LoadFromFile(path))
+	require.Equal(4, loaded.ApproxLen())
+	for i := byte(0); i < 10; i++ {
+		require.Equal(i >= 6, loaded.Has([]byte{i}), "key %d", i)
+	}
+
+	// The least accessed entry is the least recently used.
+	loaded.Set([]byte{10}, []byte("value...."))
+	require.False(loaded.Has([]byte{6}))
+	require.True(loaded.Has([]byte{7}))
+	require.NoError(loaded.CheckInvariants())
+
+	// Access counts are restored, so they order a later reload too.
+	require.NoError(loaded.SaveToFileConcurrent(path, 1))
+	reloaded := NewWithShards(20, 1)
+	require.NoError(reloaded.LoadFromFile(path))
+	require.True(reloaded.Has([]byte{9}))
+	require.True(reloaded.Has([]byte{8}))
+}
+
+func TestLoadFromFileErrors(t *testing.T) {
+	validHeader := []byte(fileMagic)
+	validHeader = binary.BigEndian.AppendUint32(validHeader, fileVersion)
+	validHeader = binary.BigEndian.AppendUint32(validHeader, 0)
+
+	tests := []struct {
+		name     string
+		contents []byte
+		err      error
+	}{
+		{
+			name:     "bad magic",
+			contents: []byte("NOPE\x00\x00\x00\x01"),
+			err:      ErrCorruptFile,
+		},
+		{
+			name:     "version mismatch",
+			contents: binary.BigEndian.AppendUint32([]byte(fileMagic), fileVersion+1),
+			err:      ErrVersionMismatch,
+		},
+		{
+			name:     "unknown flags",
+			contents: binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32([]byte(fileMagic), fileVersion), 1<<31),
+			err:      ErrVersionMismatch,
+		},
+		{
+			name:     "truncated flags",
+			contents: binary.BigEndian.AppendUint32([]byte(fileMagic), fileVersion),
+			err:      ErrShortBuffer,
+		},
+		{
+			name:     "truncated header",
+			contents: []byte(fileMagic),
+			err:      ErrShortBuffer,
+		},
+		{
+			name: "truncated record",
+			contents: append(
+				binary.BigEndian.AppendUint32(
+					binary.BigEndian.AppendUint32(append([]byte{}, validHeader...), 3),
+					3,
+				),
+				'k', 'e',
+			),
+			err: ErrShortBuffer,
+		},
+		{
+			name: "oversized record",
+			contents: binary.BigEndian.AppendUint64(
+				binary.BigEndian.AppendUint32(
+					binary.BigEndian.AppendUint32(append([]byte{}, validHeader...), 1<<30),
+					1,
+				),
+				0,
+			),
+			err: ErrCorruptFile,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cache")
+			require.NoError(t, os.WriteFile(path, test.contents, 0o600))
+
+			err := New(1 << 20).LoadFromFile(path)
+			require.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func TestLoadFromFileMissing(t *testing.T) {
+	err := New(1 << 20).LoadFromFile(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+// corruptSavedFile saves three entries to a new file and flips a byte in the
+// value of the second record, returning the path of the file.
+func corruptSavedFile(t *testing.T) string {
+	t.Helper()
+	require := require.New(t)
+
+	// Keys that land in distinct, increasing shards are written in key order.
+	c := New(1 << 20)
+	keys := [][]byte{{0}, {1}, {2}}
+	for _, key := range keys {
+		c.Set(key, []byte("value"))
+	}
+	path := filepath.Join(t.TempDir(), "cache")
+	require.NoError(c.SaveToFileConcurrent(path, 1))
+
+	contents, err := os.ReadFile(path)
+	require.NoError(err)
+	recordLen := recordHeaderLen + 1 + len("value")
+	second := headerLen + flagsLen + recordLen
+	contents[second+recordHeaderLen+1] ^= 0xff
+	require.NoError(os.WriteFile(path, contents, 0o600))
+	return path
+}
+
+func TestLoadFromFileChecksumStrict(t *testing.T) {
+	require := require.New(t)
+
+	path := corruptSavedFile(t)
+	loaded := New(1 << 20)
+	err := loaded.LoadFromFile(path)
+	require.ErrorIs(err, ErrChecksumMismatch)
+
+	// Records before the corrupted one were loaded.
+	require.True(loaded.Has([]byte{0}))
+	require.False(loaded.Has([]byte{1}))
+}
+
+func TestLoadFromFileChecksumLenient(t *testing.T) {
+	require := require.New(t)
+
+	path := corruptSavedFile(t)
+	loaded := New(1 << 20)
+	skipped, err := loaded.LoadFromFileWithMode(path, ChecksumLenient)
+	require.NoError(err)
+	require.Equal(1, skipped)
+
+	require.True(loaded.Has([]byte{0}))
+	require.False(loaded.Has([]byte{1}))
+	v, ok := loaded.HasGet(nil, []byte{2})
+	require.True(ok)
+	require.Equal([]byte("value"), v)
+}
+
+func TestLoadFromFileVersion1(t *testing.T) {
+	require := require.New(t)
+
+	contents := binary.BigEndian.AppendUint32([]byte(fileMagic), fileVersionNoChecks)
+	contents = binary.BigEndian.AppendUint32(contents, 1)
+	contents = binary.BigEndian.AppendUint32(contents, 2)
+	contents = append(contents, 'k', 'v', 'v')
+
+	path := filepath.Join(t.TempDir(), "cache")
+	require.NoError(os.WriteFile(path, contents, 0o600))
+
+	loaded := New(1 << 20)
+	require.NoError(loaded.LoadFromFile(path))
+	v, ok := loaded.HasGet(nil, []byte("k"))
+	require.True(ok)
+	require.Equal([]byte("vv"), v)
+}
+
+func TestLoadFromFileVersion2(t *testing.T) {
+	require := require.New(t)
+
+	contents := binary.BigEndian.AppendUint32([]byte(fileMagic), fileVersionNoHits)
+	contents = binary.BigEndian.AppendUint32(contents, 1)
+	contents = binary.BigEndian.AppendUint32(contents, 2)
+	contents = binary.BigEndian.AppendUint32(contents, crc32.Checksum([]byte("kvv"), crcTable))
+	contents = append(contents, 'k', 'v', 'v')
+
+	path := filepath.Join(t.TempDir(), "cache")
+	require.NoError(os.WriteFile(path, contents, 0o600))
+
+	loaded := New(1 << 20)
+	require.NoError(loaded.LoadFromFile(path))
+	v, ok := loaded.HasGet(nil, []byte("k"))
+	require.True(ok)
+	require.Equal([]byte("vv"), v)
+}
+
+func TestLoadFromFileVersion3(t *testing.T) {
+	require := require.New(t)
+
+	contents := binary.BigEndian.AppendUint32([]byte(fileMagic), fileVersionNoFlags)
+	contents = binary.BigEndian.AppendUint32(contents, 1)
+	contents = binary.BigEndian.AppendUint32(contents, 2)
+	contents = binary.BigEndian.AppendUint32(contents, crc32.Checksum([]byte("kvv"), crcTable))
+	contents = binary.BigEndian.AppendUint32(contents, 5)
+	contents = append(contents, 'k', 'v', 'v')
+
+	path := filepath.Join(t.TempDir(), "cache")
+	require.NoError(os.WriteFile(path, contents, 0o600))
+
+	loaded := New(1 << 20)
+	require.NoError(loaded.LoadFromFile(path))
+	v, ok := loaded.HasGet(nil, []byte("k"))
+	require.True(ok)
+	require.Equal([]byte("vv"), v)
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// pooledBuffer is a buffer handed out by GetPooled. Its release function is
+// bound once, when the buffer is first created, so that handing it out doesn't
+// allocate.
+type pooledBuffer struct {
+	buf     []byte
+	release func()
+}
+
+var (
+	pooledBuffers sync.Pool
+
+	noRelease = func() {}
+)
+
+// getPooledBuffer returns a recycled pooledBuffer, or a new one if the pool is
+// empty.
+func getPooledBuffer() *pooledBuffer {
+	if p, ok := pooledBuffers.Get().(*pooledBuffer); ok {
+		return p
+	}
+	p := &pooledBuffer{}
+	p.release = func() {
+		if cap(p.buf) > 1<<maxPooledClass {
+			p.buf = nil
+		}
+		pooledBuffers.Put(p)
+	}
+	return p
+}
+
+// GetPooled returns a copy of the value for key in a buffer taken from an
+// internal pool, along with a function that returns the buffer to the pool.
+// The buffer never aliases the cache's storage, so it stays valid regardless
+// of later writes, until release is called.
+//
+// release must be called exactly once, after which the buffer must not be
+// used. On a miss, GetPooled returns nil and a release function that does
+// nothing.
+func (c *Cache) GetPooled(key []byte) ([]byte, func()) {
+	atomic.AddUint64(&c.getCalls, 1)
+	s := c.shard(key)
+
+	s.mu.Lock()
+	e, ok := s.items[string(key)]
+	if !ok {
+		s.mu.Unlock()
+		atomic.AddUint64(&c.misses, 1)
+		return nil, noRelease
+	}
+	c.touch(s, e)
+
+	p := getPooledBuffer()
+	if c.codec == nil {
+		p.buf = append(p.buf[:0], e.value.buf...)
+		s.mu.Unlock()
+		return p.buf, p.release
+	}
+
+	v := e.value
+	v.acquire()
+	s.mu.Unlock()
+	val, ok := c.decode(v.buf)
+	if ok {
+		p.buf = append(p.buf[:0], val...)
+	}
+	v.release()
+	if !ok {
+		p.release()
+		atomic.AddUint64(&c.misses, 1)
+		return nil, noRelease
+	}
+	return p.buf, p.release
+}
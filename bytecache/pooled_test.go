@@ -0,0 +1,85 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPooled(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 20)
+	key := []byte("key")
+	c.Set(key, []byte("value"))
+
+	v, release := c.GetPooled(key)
+	require.Equal([]byte("value"), v)
+
+	// The buffer doesn't alias the cache's storage.
+	v[0] = 'V'
+	got, ok := c.HasGet(nil, key)
+	require.True(ok)
+	require.Equal([]byte("value"), got)
+
+	// Nor is it affected by later writes.
+	c.Set(key, []byte("other"))
+	require.Equal([]byte("Value"), v)
+	release()
+
+	v, release = c.GetPooled([]byte("missing"))
+	require.Nil(v)
+	release()
+}
+
+func TestGetPooledWithCodec(t *testing.T) {
+	require := require.New(t)
+
+	c := NewWithCompression(1<<20, NewFlateCodec(-1))
+	value := bytes.Repeat([]byte("abc"), 100)
+	c.Set([]byte("key"), value)
+
+	v, release := c.GetPooled([]byte("key"))
+	defer release()
+	require.Equal(value, v)
+}
+
+func TestGetPooledAllocs(t *testing.T) {
+	c := New(1 << 20)
+	key := []byte("key")
+	c.Set(key, []byte("value"))
+
+	// Warm the pool.
+	_, release := c.GetPooled(key)
+	release()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, release := c.GetPooled(key)
+		release()
+	})
+	require.Zero(t, allocs)
+}
+
+func BenchmarkGetPooled(b *testing.B) {
+	c := New(1 << 20)
+	key := []byte("key")
+	c.Set(key, make([]byte, 1024))
+
+	b.Run("HasGet", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = c.HasGet(nil, key)
+		}
+	})
+	b.Run("GetPooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, release := c.GetPooled(key)
+			release()
+		}
+	})
+}
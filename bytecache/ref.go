@@ -0,0 +1,147 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// maxPooledClass bounds the size of recycled buffers to 1<<maxPooledClass
+// bytes so that rare huge values don't pin memory in the pool.
+const maxPooledClass = 24
+
+// bufferPools holds recycled value buffers. Pool i only holds buffers with a
+// capacity in [1<<i, 1<<(i+1)).
+var bufferPools [maxPooledClass + 1]sync.Pool
+
+// getBuffer returns a buffer of length n, reusing a recycled one if possible.
+func getBuffer(n int) []byte {
+	class := bits.Len(uint(max(n, 1) - 1))
+	if class <= maxPooledClass {
+		if buf, ok := bufferPools[class].Get().(*[]byte); ok {
+			return (*buf)[:n]
+		}
+	}
+	return make([]byte, n)
+}
+
+// putBuffer recycles buf for use by a later getBuffer call. buf must not be
+// used by the caller afterwards.
+func putBuffer(buf []byte) {
+	if cap(buf) == 0 {
+		return
+	}
+	class := bits.Len(uint(cap(buf))) - 1
+	if class > maxPooledClass {
+		return
+	}
+	buf = buf[:0]
+	bufferPools[class].Put(&buf)
+}
+
+// byteValue is a reference counted value buffer.
+//
+// The cache holds one reference while the value is stored. Readers acquire
+// another reference, while holding the shard lock, for as long as they read
+// the buffer without the lock. Whoever releases the last reference recycles
+// the buffer, so a buffer is never reused while it may still be read.
+type byteValue struct {
+	buf  []byte
+	refs int32
+	// owned is false when buf has been provided by the caller, in which case
+	// it is never recycled.
+	owned bool
+}
+
+// newByteValue returns a value holding buf with the cache's reference.
+func newByteValue(buf []byte, owned bool) *byteValue {
+	return &byteValue{
+		buf:   buf,
+		refs:  1,
+		owned: owned,
+	}
+}
+
+// copyValue returns a cache owned copy of src.
+func copyValue(src []byte) *byteValue {
+	buf := getBuffer(len(src))
+	copy(buf, src)
+	return newByteValue(buf, true)
+}
+
+func (v *byteValue) acquire() {
+	atomic.AddInt32(&v.refs, 1)
+}
+
+func (v *byteValue) release() {
+	if atomic.AddInt32(&v.refs, -1) == 0 && v.owned {
+		putBuffer(v.buf)
+		v.buf = nil
+	}
+}
+
+// RefValue is a handle to a value stored in a [Cache] that shares the cache's
+// buffer instead of copying it.
+//
+// The buffer remains valid, even if the entry is evicted or replaced, until
+// Release is called. Forgetting to call Release doesn't leak memory, as the
+// buffer is still garbage collected, but it prevents the buffer from being
+// recycled. Using the bytes after calling Release is a serious bug: the buffer
+// may already hold a different value.
+type RefValue struct {
+	value    *byteValue
+	buf      []byte
+	released uint32
+}
+
+// Bytes returns the shared value. The returned slice must not be modified and
+// must not be used after Release.
+func (r *RefValue) Bytes() []byte {
+	return r.buf
+}
+
+// Release gives up the handle's reference to the buffer. It is safe to call
+// more than once.
+func (r *RefValue) Release() {
+	if !atomic.CompareAndSwapUint32(&r.released, 0, 1) {
+		return
+	}
+	if r.value != nil {
+		r.value.release()
+	}
+}
+
+// GetRef returns a handle to the value for key without copying it.
+//
+// If the cache was created with a [Codec], the stored value must be decoded,
+// so the handle holds a private decoded copy instead.
+func (c *Cache) GetRef(key []byte) (*RefValue, bool) {
+	atomic.AddUint64(&c.getCalls, 1)
+	s := c.shard(key)
+
+	s.mu.Lock()
+	e, ok := s.items[string(key)]
+	if !ok {
+		s.mu.Unlock()
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	c.touch(s, e)
+	v := e.value
+	v.acquire()
+	s.mu.Unlock()
+
+	if c.codec == nil {
+		return &RefValue{value: v, buf: v.buf}, true
+	}
+	decoded, ok := c.decode(v.buf)
+	v.release()
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	return &RefValue{buf: decoded}, true
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRefSurvivesEviction(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		maxBytes  = numShards * 16
+		valueSize = 8
+	)
+	c := New(maxBytes)
+	original := bytes.Repeat([]byte{0xaa}, valueSize)
+	c.Set(sameShardKey(0), original)
+
+	ref, ok := c.GetRef(sameShardKey(0))
+	require.True(ok)
+	require.Equal(original, ref.Bytes())
+
+	// Each shard only fits one entry, so every Set evicts the previous entry
+	// and recycles its buffer unless it is still referenced.
+	for i := 1; i < 100; i++ {
+		c.Set(sameShardKey(i), bytes.Repeat([]byte{byte(i)}, valueSize))
+	}
+	require.False(c.Has(sameShardKey(0)))
+	require.Equal(original, ref.Bytes())
+
+	ref.Release()
+	ref.Release()
+
+	_, ok = c.GetRef(sameShardKey(0))
+	require.False(ok)
+}
+
+func TestGetRefReplacedValue(t *testing.T) {
+	require := require.New(t)
+
+	c := New(1 << 20)
+	c.Set([]byte("key"), []byte("old"))
+	ref, ok := c.GetRef([]byte("key"))
+	require.True(ok)
+
+	for i := 0; i < 10; i++ {
+		c.Set([]byte("key"), []byte("new"))
+	}
+	require.Equal([]byte("old"), ref.Bytes())
+	ref.Release()
+
+	v, ok := c.HasGet(nil, []byte("key"))
+	require.True(ok)
+	require.Equal([]byte("new"), v)
+}
+
+func TestBufferPool(t *testing.T) {
+	require := require.New(t)
+
+	for _, n := range []int{0, 1, 2, 3, 7, 8, 9, 1000, 1 << 24, 1<<24 + 1} {
+		buf := getBuffer(n)
+		require.Len(buf, n)
+		putBuffer(buf)
+	}
+}
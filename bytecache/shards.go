@@ -0,0 +1,77 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"math/bits"
+	"os"
+	"runtime"
+	"strconv"
+)
+
+const (
+	// ShardsEnv names the environment variable that, when set to a positive
+	// integer, overrides the number of shards chosen by NewAuto.
+	ShardsEnv = "BYTECACHE_SHARDS"
+
+	// shardsPerProc is how many shards NewAuto creates per GOMAXPROCS, so
+	// that goroutines rarely contend for a shard even when keys are skewed.
+	shardsPerProc = 4
+	// minAutoShards and maxAutoShards bound the number of shards chosen from
+	// GOMAXPROCS.
+	minAutoShards = 16
+	maxAutoShards = 1024
+
+	// maxShards bounds the number of shards of any cache.
+	maxShards = 1 << 16
+)
+
+// NewWithShards creates a byte cache split into the given number of shards,
+// rounded up to a power of two between 1 and 65536, each limited to an equal
+// share of maxBytes. More shards reduce lock contention, while each shard
+// costs a map and bookkeeping even when empty and shares a smaller slice of
+// maxBytes, so large values are rejected sooner.
+func NewWithShards(maxBytes, shards int) *Cache {
+	return NewWithOptions(Options{MaxBytes: maxBytes, Shards: max(shards, 1)})
+}
+
+// NewAuto creates a byte cache whose number of shards fits the machine,
+// instead of the fixed 256 of New.
+//
+// The number of shards is four per GOMAXPROCS, rounded up to a power of two
+// and bounded to [16, 1024]: 16 shards for up to 4 procs, 64 for 16 procs and
+// 512 for 128 procs. If the ShardsEnv environment variable is set to a
+// positive integer, it is used instead, as by NewWithShards.
+func NewAuto(maxBytes int) *Cache {
+	return NewWithOptions(Options{MaxBytes: maxBytes, AutoShards: true})
+}
+
+// Shards returns the number of shards of the cache.
+func (c *Cache) Shards() int {
+	return len(c.shards)
+}
+
+// defaultAutoShards returns the number of shards NewAuto picks.
+func defaultAutoShards() int {
+	if n, err := strconv.Atoi(os.Getenv(ShardsEnv)); err == nil && n > 0 {
+		return roundShards(n)
+	}
+	return autoShards(runtime.GOMAXPROCS(0))
+}
+
+// autoShards returns the number of shards NewAuto picks for procs.
+func autoShards(procs int) int {
+	return min(max(roundShards(procs*shardsPerProc), minAutoShards), maxAutoShards)
+}
+
+// roundShards rounds n up to a power of two in [1, maxShards].
+func roundShards(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	if n >= maxShards {
+		return maxShards
+	}
+	return 1 << bits.Len(uint(n-1))
+}
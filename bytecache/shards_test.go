@@ -0,0 +1,77 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoShards(t *testing.T) {
+	tests := []struct {
+		procs  int
+		shards int
+	}{
+		{procs: 1, shards: 16},
+		{procs: 4, shards: 16},
+		{procs: 5, shards: 32},
+		{procs: 16, shards: 64},
+		{procs: 48, shards: 256},
+		{procs: 128, shards: 512},
+		{procs: 256, shards: 1024},
+		{procs: 1024, shards: 1024},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.procs), func(t *testing.T) {
+			require.Equal(t, test.shards, autoShards(test.procs))
+		})
+	}
+}
+
+func TestNewAuto(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(autoShards(runtime.GOMAXPROCS(0)), NewAuto(1<<20).Shards())
+
+	t.Setenv(ShardsEnv, "100")
+	require.Equal(128, NewAuto(1<<20).Shards())
+
+	t.Setenv(ShardsEnv, "invalid")
+	require.Equal(autoShards(runtime.GOMAXPROCS(0)), NewAuto(1<<20).Shards())
+}
+
+func TestNewWithShards(t *testing.T) {
+	tests := []struct {
+		shards int
+		want   int
+	}{
+		{shards: -1, want: 1},
+		{shards: 1, want: 1},
+		{shards: 3, want: 4},
+		{shards: 256, want: 256},
+		{shards: 1000, want: 1024},
+		{shards: 1 << 20, want: maxShards},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.shards), func(t *testing.T) {
+			require := require.New(t)
+
+			c := NewWithShards(1<<26, test.shards)
+			require.Equal(test.want, c.Shards())
+
+			for i := range 1000 {
+				key := fmt.Appendf(nil, "key-%d", i)
+				c.Set(key, key)
+			}
+			for i := range 1000 {
+				key := fmt.Appendf(nil, "key-%d", i)
+				require.Equal(key, c.Get(nil, key))
+			}
+			require.Equal(1000, c.ApproxLen())
+		})
+	}
+}
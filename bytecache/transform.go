@@ -0,0 +1,89 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+)
+
+var (
+	_ Transform = (*AESGCM)(nil)
+	_ Codec     = transformCodec{}
+)
+
+// Transform encodes values as they are stored in the cache and decodes them
+// as they are read, for instance to keep them encrypted in memory. Keys are
+// stored as given.
+type Transform interface {
+	// Encode returns the stored form of src. The returned slice must not
+	// alias src.
+	Encode(src []byte) []byte
+	// Decode returns the original value encoded by Encode.
+	Decode(src []byte) ([]byte, error)
+}
+
+// NewWithTransform creates a byte cache that stores every value encoded by t
+// and decodes it on Get. The encoded size is what counts against maxBytes.
+// Values that fail to decode are reported as missing by Get and HasGet, and
+// GetChecked returns the error. A nil t stores values as given.
+//
+// SaveToFileConcurrent writes values as encoded, so that the file can only be
+// loaded by a cache with the same Transform, while Drain returns decoded
+// values.
+func NewWithTransform(maxBytes int, t Transform) *Cache {
+	return NewWithOptions(Options{MaxBytes: maxBytes, Transform: t})
+}
+
+// transformCodec adapts a Transform to the Codec the cache applies to values,
+// compressing them with codec first if it is non-nil.
+type transformCodec struct {
+	t     Transform
+	codec Codec
+}
+
+func (t transformCodec) Compress(src []byte) []byte {
+	if t.codec != nil {
+		src = t.codec.Compress(src)
+	}
+	return t.t.Encode(src)
+}
+
+func (t transformCodec) Decompress(src []byte) ([]byte, error) {
+	decoded, err := t.t.Decode(src)
+	if err != nil || t.codec == nil {
+		return decoded, err
+	}
+	return t.codec.Decompress(decoded)
+}
+
+// AESGCM is a [Transform] encrypting values with AES-GCM under a random nonce
+// per value. Each encoded value is 28 bytes longer than the original: a 12
+// byte nonce and a 16 byte authentication tag. Values that were tampered with
+// fail to decode.
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM returns an AES-GCM transform using key, which must be 16, 24 or 32
+// bytes long to select AES-128, AES-192 or AES-256.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCMWithRandomNonce(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCM{aead: aead}, nil
+}
+
+func (a *AESGCM) Encode(src []byte) []byte {
+	return a.aead.Seal(nil, nil, src, nil)
+}
+
+func (a *AESGCM) Decode(src []byte) ([]byte, error) {
+	return a.aead.Open(nil, nil, src, nil)
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAESGCMTransform(t *testing.T) {
+	require := require.New(t)
+
+	aesgcm, err := NewAESGCM(bytes.Repeat([]byte{7}, 32))
+	require.NoError(err)
+	c := NewWithTransform(1<<20, aesgcm)
+
+	var (
+		key    = []byte("secret")
+		secret = []byte("correct horse battery staple")
+	)
+	c.Set(key, secret)
+
+	s := c.shard(key)
+	stored := s.items[string(key)].value.buf
+	require.NotContains(string(stored), string(secret))
+	require.Len(stored, len(secret)+28)
+	require.Equal(int64(len(key)+len(stored)), c.ApproxBytes())
+
+	require.Equal(secret, c.Get(nil, key))
+	got, ok, err := c.GetChecked(nil, key)
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(secret, got)
+
+	// Tampering with the ciphertext fails authentication.
+	stored[len(stored)-1] ^= 1
+	got, ok, err = c.GetChecked(nil, key)
+	require.ErrorIs(err, ErrDecode)
+	require.False(ok)
+	require.Empty(got)
+	_, ok = c.HasGet(nil, key)
+	require.False(ok)
+
+	_, ok, err = c.GetChecked(nil, []byte("missing"))
+	require.NoError(err)
+	require.False(ok)
+}
+
+func TestNewAESGCMInvalidKey(t *testing.T) {
+	_, err := NewAESGCM([]byte("short"))
+	require.Error(t, err)
+}
+
+func TestAESGCMTransformPersistsEncrypted(t *testing.T) {
+	require := require.New(t)
+
+	aesgcm, err := NewAESGCM(bytes.Repeat([]byte{7}, 32))
+	require.NoError(err)
+	var (
+		path   = filepath.Join(t.TempDir(), "cache")
+		key    = []byte("secret")
+		secret = []byte("correct horse battery staple")
+	)
+	c := NewWithTransform(1<<20, aesgcm)
+	c.Set(key, secret)
+	require.NoError(c.SaveToFileConcurrent(path, 1))
+
+	contents, err := os.ReadFile(path)
+	require.NoError(err)
+	require.NotContains(string(contents), string(secret))
+
+	loaded := NewWithTransform(1<<20, aesgcm)
+	require.NoError(loaded.LoadFromFile(path))
+	require.Equal(secret, loaded.Get(nil, key))
+
+	// Without the transform, the values can't be read back.
+	require.ErrorIs(New(1<<20).LoadFromFile(path), ErrTransformMismatch)
+
+	// Nor with another key.
+	other, err := NewAESGCM(bytes.Repeat([]byte{8}, 32))
+	require.NoError(err)
+	require.ErrorIs(NewWithTransform(1<<20, other).LoadFromFile(path), ErrDecode)
+	skipped, err := NewWithTransform(1<<20, other).LoadFromFileWithMode(path, ChecksumLenient)
+	require.NoError(err)
+	require.Equal(1, skipped)
+}
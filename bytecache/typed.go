@@ -0,0 +1,52 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+// TypedByteCache is a view of a [Cache] keyed by values of type K rather than
+// raw bytes. Every key is converted with the encoder given to NewTyped, which
+// must be deterministic and injective: distinct keys must encode to distinct
+// bytes, or they will share an entry.
+//
+// Values are stored as given. To compress them, create the underlying cache
+// with [NewWithCompression].
+type TypedByteCache[K any] struct {
+	cache  *Cache
+	encode func(K) []byte
+}
+
+// NewTyped returns a typed view of c using encode to convert keys. The view
+// doesn't own c, which may still be used directly.
+func NewTyped[K any](c *Cache, encode func(K) []byte) *TypedByteCache[K] {
+	return &TypedByteCache[K]{
+		cache:  c,
+		encode: encode,
+	}
+}
+
+// Cache returns the underlying cache.
+func (t *TypedByteCache[K]) Cache() *Cache {
+	return t.cache
+}
+
+// Get returns the value of key and whether it exists. The value is a copy
+// unless the cache's [CopyPolicy] shares it.
+func (t *TypedByteCache[K]) Get(key K) ([]byte, bool) {
+	return t.cache.HasGet(nil, t.encode(key))
+}
+
+// Set stores value under key, copied unless the cache's [CopyPolicy] is
+// NoCopy.
+func (t *TypedByteCache[K]) Set(key K, value []byte) {
+	t.cache.Set(t.encode(key), value)
+}
+
+// Has returns whether key exists.
+func (t *TypedByteCache[K]) Has(key K) bool {
+	return t.cache.Has(t.encode(key))
+}
+
+// Del removes key.
+func (t *TypedByteCache[K]) Del(key K) {
+	t.cache.Del(t.encode(key))
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bytecache
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type blockKey struct {
+	height uint64
+	index  uint32
+}
+
+func encodeBlockKey(k blockKey) []byte {
+	b := make([]byte, 12)
+	binary.BigEndian.PutUint64(b, k.height)
+	binary.BigEndian.PutUint32(b[8:], k.index)
+	return b
+}
+
+func TestTypedByteCache(t *testing.T) {
+	require := require.New(t)
+
+	c := NewTyped(New(1<<20), encodeBlockKey)
+	keys := []blockKey{
+		{height: 1, index: 0},
+		{height: 0, index: 1},
+		{height: 1, index: 1},
+	}
+	for i, k := range keys {
+		c.Set(k, []byte{byte(i)})
+	}
+	for i, k := range keys {
+		v, ok := c.Get(k)
+		require.True(ok)
+		require.Equal([]byte{byte(i)}, v)
+	}
+
+	_, ok := c.Get(blockKey{})
+	require.False(ok)
+
+	c.Del(keys[0])
+	require.False(c.Has(keys[0]))
+	require.True(c.Has(keys[1]))
+
+	// The underlying cache sees the encoded keys.
+	require.True(c.Cache().Has(encodeBlockKey(keys[2])))
+}
@@ -2,8 +2,26 @@
 // See the file LICENSE for licensing terms.
 
 // Package cache provides caching interfaces and implementations.
+//
+// Every cache implements [Cacher]. Some caches also implement optional
+// interfaces that tooling can detect with a type assertion:
+//
+//   - [Iterable]: [DualMapCache], [ShardedDualMapCache], lru.Cache,
+//     lru.SizedCache and lru.ShardedSizedCache.
+//   - [SizeReporter] and [ByteSized]: lru.SizedCache, lru.ShardedSizedCache
+//     and bytecache.Cache.
+//   - [BoundReporter]: every cache in this module. [DualMapCache] and
+//     [ShardedDualMapCache] report themselves as unbounded, all others as
+//     bounded. Wrappers such as [ReadOnly], [ReplicaCache] and
+//     metercacher.Cache report the boundedness of the cache they wrap, and
+//     [FromContainer] that of the container.Cache it wraps, if it can tell.
+//   - [StatsReporter]: [DualMapCache], [ShardedDualMapCache], lru.SizedCache,
+//     lru.ShardedSizedCache and metercacher.Cache. lru.Cache reports richer
+//     lru.CacheStats, which convert to [Stats] with their Standard method.
 package cache
 
+import "iter"
+
 // Cacher acts as a best effort key value store.
 type Cacher[K comparable, V any] interface {
 	// Put inserts an element into the cache.
@@ -24,3 +42,47 @@ type Cacher[K comparable, V any] interface {
 	// PortionFilled returns fraction of cache currently filled (0 --> 1).
 	PortionFilled() float64
 }
+
+// Iterable is implemented by caches that can enumerate their entries.
+type Iterable[K comparable, V any] interface {
+	// All returns an iterator over the entries of the cache. Caches with a
+	// recency order yield entries from most to least recently used.
+	All() iter.Seq2[K, V]
+}
+
+// BoundReporter is implemented by caches that report whether they are bounded.
+// The PortionFilled of an unbounded cache is not meaningful: it is 1 as soon
+// as the cache holds any entry.
+type BoundReporter interface {
+	// Bounded reports whether the cache limits the number or total size of
+	// its entries.
+	Bounded() bool
+}
+
+// IsBounded reports whether c is bounded. Caches that do not implement
+// [BoundReporter] are assumed to be bounded.
+func IsBounded[K comparable, V any](c Cacher[K, V]) bool {
+	if reporter, ok := c.(BoundReporter); ok {
+		return reporter.Bounded()
+	}
+	return true
+}
+
+// SizeReporter is implemented by caches that are bounded by the total size of
+// their entries, typically in bytes, rather than by their number.
+type SizeReporter interface {
+	// Cap returns the maximum total size of the cache.
+	Cap() int
+	// Size returns the current total size of the cache.
+	Size() int
+}
+
+// ByteSized is implemented by caches that account for the memory their entries
+// use in bytes, so that monitoring can report byte usage whatever the concrete
+// cache. It complements the entry counts of [Cacher].
+type ByteSized interface {
+	// Bytes returns the number of bytes currently held by the cache.
+	Bytes() int64
+	// MaxBytes returns the maximum number of bytes the cache may hold.
+	MaxBytes() int64
+}
@@ -0,0 +1,36 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// unreported hides the BoundReporter implementation of the cache it wraps.
+type unreported struct {
+	Cacher[int, int]
+}
+
+func TestIsBounded(t *testing.T) {
+	tests := []struct {
+		name    string
+		cache   Cacher[int, int]
+		bounded bool
+	}{
+		{name: "empty", cache: &Empty[int, int]{}, bounded: true},
+		{name: "lru", cache: NewLRU[int, int](2), bounded: true},
+		{name: "dual map", cache: NewDualMapCache[int, int](nil), bounded: false},
+		{name: "sharded dual map", cache: NewDualMapCacheSharded[int, int](2), bounded: false},
+		{name: "read-only bounded", cache: ReadOnly[int, int](NewLRU[int, int](2)), bounded: true},
+		{name: "read-only unbounded", cache: ReadOnly[int, int](NewDualMapCache[int, int](nil)), bounded: false},
+		{name: "unreported", cache: unreported{NewDualMapCache[int, int](nil)}, bounded: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.bounded, IsBounded(test.cache))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import "github.com/luxfi/container"
+
+var (
+	_ Cacher[struct{}, struct{}]          = (*fromContainer[struct{}, struct{}])(nil)
+	_ BoundReporter                       = (*fromContainer[struct{}, struct{}])(nil)
+	_ container.Cache[struct{}, struct{}] = (*toContainer[struct{}, struct{}])(nil)
+)
+
+type fromContainer[K comparable, V any] struct {
+	cache container.Cache[K, V]
+}
+
+// FromContainer returns a Cacher backed by c. Put, Get, Evict and Len are
+// delegated to c, while Flush and PortionFilled, which container.Cache lacks,
+// are delegated to the methods of c described below when it has them:
+//
+//   - Flush calls Clear or Flush. Without either it can't enumerate the
+//     entries of c and does nothing.
+//   - PortionFilled calls PortionFilled, or divides Len by Cap. Without either
+//     c is treated as unbounded: PortionFilled is 1 as soon as c holds an
+//     entry, and the returned cache reports itself as unbounded.
+//
+// If c was returned by ToContainer, the cache it wraps is returned instead.
+func FromContainer[K comparable, V any](c container.Cache[K, V]) Cacher[K, V] {
+	if t, ok := c.(*toContainer[K, V]); ok {
+		return t.cache
+	}
+	return &fromContainer[K, V]{cache: c}
+}
+
+func (f *fromContainer[K, V]) Put(key K, value V) {
+	f.cache.Put(key, value)
+}
+
+func (f *fromContainer[K, V]) Get(key K) (V, bool) {
+	return f.cache.Get(key)
+}
+
+func (f *fromContainer[K, _]) Evict(key K) {
+	f.cache.Evict(key)
+}
+
+func (f *fromContainer[_, _]) Flush() {
+	switch c := f.cache.(type) {
+	case interface{ Clear() }:
+		c.Clear()
+	case interface{ Flush() }:
+		c.Flush()
+	}
+}
+
+func (f *fromContainer[_, _]) Len() int {
+	return f.cache.Len()
+}
+
+func (f *fromContainer[_, _]) PortionFilled() float64 {
+	switch c := f.cache.(type) {
+	case interface{ PortionFilled() float64 }:
+		return c.PortionFilled()
+	case interface{ Cap() int }:
+		if capacity := c.Cap(); capacity > 0 {
+			return float64(f.cache.Len()) / float64(capacity)
+		}
+		return 0
+	}
+	return portionFilled(f.cache.Len())
+}
+
+func (f *fromContainer[_, _]) Bounded() bool {
+	switch c := f.cache.(type) {
+	case BoundReporter:
+		return c.Bounded()
+	case interface{ PortionFilled() float64 }, interface{ Cap() int }:
+		return true
+	}
+	return false
+}
+
+type toContainer[K comparable, V any] struct {
+	cache Cacher[K, V]
+}
+
+// ToContainer returns a container.Cache backed by c. Delete and Evict both
+// call Evict of c. If c was returned by FromContainer, the container.Cache it
+// wraps is returned instead.
+func ToContainer[K comparable, V any](c Cacher[K, V]) container.Cache[K, V] {
+	if f, ok := c.(*fromContainer[K, V]); ok {
+		return f.cache
+	}
+	return &toContainer[K, V]{cache: c}
+}
+
+func (t *toContainer[K, V]) Put(key K, value V) {
+	t.cache.Put(key, value)
+}
+
+func (t *toContainer[K, V]) Get(key K) (V, bool) {
+	return t.cache.Get(key)
+}
+
+func (t *toContainer[K, _]) Delete(key K) {
+	t.cache.Evict(key)
+}
+
+func (t *toContainer[K, _]) Evict(key K) {
+	t.cache.Evict(key)
+}
+
+func (t *toContainer[_, _]) Len() int {
+	return t.cache.Len()
+}
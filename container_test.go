@@ -0,0 +1,89 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/container"
+)
+
+func TestFromContainer(t *testing.T) {
+	require := require.New(t)
+
+	c := FromContainer[int, int](container.NewLRUCache[int, int](2))
+	require.Zero(c.PortionFilled())
+	require.False(IsBounded(c))
+
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Put(3, 3)
+	_, ok := c.Get(1)
+	require.False(ok)
+	v, ok := c.Get(3)
+	require.True(ok)
+	require.Equal(3, v)
+	require.Equal(2, c.Len())
+	require.Equal(1.0, c.PortionFilled())
+
+	c.Evict(3)
+	require.Equal(1, c.Len())
+}
+
+func TestFromContainerDelegates(t *testing.T) {
+	require := require.New(t)
+
+	lru := NewLRU[int, int](4)
+	c := FromContainer(container.Cache[int, int](&containerOf[int, int]{LRU: lru}))
+	c.Put(1, 1)
+	require.Equal(0.25, c.PortionFilled())
+	require.True(IsBounded(c))
+
+	c.Flush()
+	require.Zero(c.Len())
+}
+
+// containerOf exposes an LRU as a container.Cache that also has Clear and
+// PortionFilled.
+type containerOf[K comparable, V any] struct {
+	*LRU[K, V]
+}
+
+func (c *containerOf[K, _]) Delete(key K) {
+	c.Evict(key)
+}
+
+func (c *containerOf[_, _]) Clear() {
+	c.Flush()
+}
+
+func TestToContainer(t *testing.T) {
+	require := require.New(t)
+
+	lru := NewLRU[int, int](2)
+	c := ToContainer[int, int](lru)
+	c.Put(1, 1)
+	c.Put(2, 2)
+	v, ok := c.Get(1)
+	require.True(ok)
+	require.Equal(1, v)
+	require.Equal(2, c.Len())
+
+	c.Delete(1)
+	c.Evict(2)
+	require.Zero(c.Len())
+	require.Zero(lru.Len())
+}
+
+func TestContainerRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	lru := NewLRU[int, int](2)
+	require.Same(lru, FromContainer(ToContainer[int, int](lru)))
+
+	inner := container.NewLRUCache[int, int](2)
+	require.Same(inner, ToContainer(FromContainer[int, int](inner)))
+}
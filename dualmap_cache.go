@@ -4,11 +4,20 @@
 package cache
 
 import (
+	"iter"
+	"maps"
 	"sync"
 
 	"github.com/luxfi/metric"
 )
 
+var (
+	_ Cacher[struct{}, struct{}]   = (*DualMapCache[struct{}, struct{}])(nil)
+	_ Iterable[struct{}, struct{}] = (*DualMapCache[struct{}, struct{}])(nil)
+	_ StatsReporter                = (*DualMapCache[struct{}, struct{}])(nil)
+	_ BoundReporter                = (*DualMapCache[struct{}, struct{}])(nil)
+)
+
 // DualMapCache is a simple two-map cache placeholder with migration hooks.
 // The implementation is intentionally minimal to preserve API compatibility.
 type DualMapCache[K comparable, V any] struct {
@@ -30,6 +39,16 @@ func (c *DualMapCache[K, V]) Put(key K, value V) {
 	c.items[key] = value
 }
 
+// PutReporting is like Put, but also reports whether it inserted a new entry,
+// rather than replacing an existing one, as a single atomic step.
+func (c *DualMapCache[K, V]) PutReporting(key K, value V) (inserted bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, existed := c.items[key]
+	c.items[key] = value
+	return !existed
+}
+
 // Get returns the entry with the key, if it exists.
 func (c *DualMapCache[K, V]) Get(key K) (V, bool) {
 	c.mu.RLock()
@@ -38,6 +57,20 @@ func (c *DualMapCache[K, V]) Get(key K) (V, bool) {
 	return val, ok
 }
 
+// GetOrDefault returns the value of key, or def if the key is missing.
+func (c *DualMapCache[K, V]) GetOrDefault(key K, def V) V {
+	if value, ok := c.Get(key); ok {
+		return value
+	}
+	return def
+}
+
+// GetOrZero returns the value of key, or the zero value if the key is missing.
+func (c *DualMapCache[K, V]) GetOrZero(key K) V {
+	value, _ := c.Get(key)
+	return value
+}
+
 // Evict removes the specified entry from the cache.
 func (c *DualMapCache[K, V]) Evict(key K) {
 	c.mu.Lock()
@@ -45,6 +78,83 @@ func (c *DualMapCache[K, V]) Evict(key K) {
 	delete(c.items, key)
 }
 
+// DelGet removes key and returns its value, reporting whether it was present,
+// under a single lock acquisition.
+func (c *DualMapCache[K, V]) DelGet(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.items[key]
+	if ok {
+		delete(c.items, key)
+	}
+	return value, ok
+}
+
+// EvictMany removes every listed key under a single lock acquisition and
+// returns the number of entries removed. Missing and repeated keys are
+// ignored.
+func (c *DualMapCache[K, V]) EvictMany(keys []K) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for _, key := range keys {
+		if _, ok := c.items[key]; ok {
+			delete(c.items, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// EvictFunc removes every entry for which pred returns true and returns the
+// number of entries removed, holding the lock for the whole pass. pred must not
+// call back into the cache.
+func (c *DualMapCache[K, V]) EvictFunc(pred func(K, V) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key, value := range c.items {
+		if pred(key, value) {
+			delete(c.items, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// All returns an iterator over the entries of the cache in no particular
+// order. The entries are snapshotted when iteration starts, so the loop body
+// may safely call back into the cache.
+func (c *DualMapCache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		c.mu.RLock()
+		items := maps.Clone(c.items)
+		c.mu.RUnlock()
+
+		for key, value := range items {
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// Compact rebuilds the internal map at a size proportional to the current
+// number of entries, releasing the memory Go maps retain after deletions. It
+// copies every entry, so it should be called occasionally rather than after
+// every removal.
+func (c *DualMapCache[K, V]) Compact() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	items := make(map[K]V, len(c.items))
+	for key, value := range c.items {
+		items[key] = value
+	}
+	c.items = items
+}
+
 // Flush removes all entries from the cache.
 func (c *DualMapCache[K, V]) Flush() {
 	c.mu.Lock()
@@ -61,13 +171,40 @@ func (c *DualMapCache[K, V]) Len() int {
 
 // PortionFilled returns fraction of cache currently filled.
 func (c *DualMapCache[K, V]) PortionFilled() float64 {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	if len(c.items) == 0 {
+	return portionFilled(c.Len())
+}
+
+// Bounded returns false, as DualMapCache never evicts on its own.
+func (*DualMapCache[_, _]) Bounded() bool {
+	return false
+}
+
+// Stats returns the length of the cache. DualMapCache is unbounded and keeps
+// no counters, so every other field is [Untracked], and PortionFilled is as
+// reported by the method of the same name.
+func (c *DualMapCache[K, V]) Stats() Stats {
+	n := c.Len()
+	return Stats{
+		Hits:          Untracked,
+		Misses:        Untracked,
+		Evictions:     Untracked,
+		Len:           n,
+		Cap:           Untracked,
+		PortionFilled: portionFilled(n),
+	}
+}
+
+// portionFilled reports an unbounded cache as full once it holds an entry.
+func portionFilled(n int) float64 {
+	if n == 0 {
 		return 0
 	}
 	return 1
 }
 
 // Migrate is a no-op placeholder for dual-map cache migration.
+//
+// Any migration must decide whether an entry is present by map membership
+// rather than by its value, so that entries holding a zero value, such as a
+// nil pointer, stay present.
 func (c *DualMapCache[K, V]) Migrate() {}
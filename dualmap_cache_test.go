@@ -0,0 +1,188 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"maps"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDualMapCacheEvictFunc(t *testing.T) {
+	require := require.New(t)
+
+	c := NewDualMapCache[int, string](nil)
+	c.Put(1, "keep")
+	c.Put(2, "drop")
+	c.Put(3, "drop")
+
+	removed := c.EvictFunc(func(_ int, v string) bool {
+		return v == "drop"
+	})
+	require.Equal(2, removed)
+	require.Equal(1, c.Len())
+
+	v, ok := c.Get(1)
+	require.True(ok)
+	require.Equal("keep", v)
+}
+
+func TestDualMapCacheAll(t *testing.T) {
+	require := require.New(t)
+
+	c := NewDualMapCache[int, string](nil)
+	c.Put(1, "one")
+	c.Put(2, "two")
+	require.Equal(map[int]string{1: "one", 2: "two"}, maps.Collect(c.All()))
+
+	count := 0
+	for range c.All() {
+		count++
+		break
+	}
+	require.Equal(1, count)
+}
+
+func TestDualMapCacheEvictMany(t *testing.T) {
+	require := require.New(t)
+
+	c := NewDualMapCache[int, int](nil)
+	for i := 0; i < 4; i++ {
+		c.Put(i, i)
+	}
+	require.Equal(2, c.EvictMany([]int{1, 2, 2, 7}))
+	require.Equal(2, c.Len())
+	_, ok := c.Get(1)
+	require.False(ok)
+}
+
+func TestDualMapCacheMigrateKeepsNilValues(t *testing.T) {
+	require := require.New(t)
+
+	c := NewDualMapCache[int, *int](nil)
+	c.Put(1, nil)
+	c.Migrate()
+
+	v, ok := c.Get(1)
+	require.True(ok)
+	require.Nil(v)
+	require.Equal(1, c.Len())
+
+	_, ok = c.Get(2)
+	require.False(ok)
+}
+
+func TestDualMapCacheGetOrDefault(t *testing.T) {
+	require := require.New(t)
+
+	c := NewDualMapCache[int, string](nil)
+	c.Put(1, "one")
+	require.Equal("one", c.GetOrDefault(1, "default"))
+	require.Equal("default", c.GetOrDefault(2, "default"))
+	require.Equal("one", c.GetOrZero(1))
+	require.Empty(c.GetOrZero(2))
+}
+
+func TestDualMapCacheStats(t *testing.T) {
+	require := require.New(t)
+
+	c := NewDualMapCache[int, int](nil)
+	require.Equal(Stats{
+		Hits:      Untracked,
+		Misses:    Untracked,
+		Evictions: Untracked,
+		Cap:       Untracked,
+	}, c.Stats())
+
+	c.Put(1, 1)
+	stats := c.Stats()
+	require.Equal(1, stats.Len)
+	require.Equal(1.0, stats.PortionFilled)
+
+	sharded := NewDualMapCacheSharded[int, int](4)
+	sharded.Put(1, 1)
+	sharded.Put(2, 2)
+	require.Equal(Stats{
+		Hits:          Untracked,
+		Misses:        Untracked,
+		Evictions:     Untracked,
+		Len:           2,
+		Cap:           Untracked,
+		PortionFilled: 1,
+	}, sharded.Stats())
+}
+
+func TestDualMapCacheCompact(t *testing.T) {
+	require := require.New(t)
+
+	c := NewDualMapCache[int, int](nil)
+	for i := range 1000 {
+		c.Put(i, i)
+	}
+	c.EvictFunc(func(k, _ int) bool { return k >= 10 })
+	c.Compact()
+
+	require.Equal(10, c.Len())
+	for i := range 10 {
+		require.Equal(i, c.GetOrZero(i))
+	}
+
+	sharded := NewDualMapCacheSharded[int, int](4)
+	sharded.Put(1, 1)
+	sharded.Compact()
+	require.Equal(1, sharded.GetOrZero(1))
+}
+
+func TestDualMapCacheDelGet(t *testing.T) {
+	for _, c := range []interface {
+		Cacher[int, int]
+		DelGet(int) (int, bool)
+	}{
+		NewDualMapCache[int, int](nil),
+		NewDualMapCacheSharded[int, int](4),
+	} {
+		require := require.New(t)
+
+		c.Put(1, 10)
+		const callers = 8
+		var (
+			wg  sync.WaitGroup
+			got = make(chan int, callers)
+		)
+		for range callers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if v, ok := c.DelGet(1); ok {
+					got <- v
+				}
+			}()
+		}
+		wg.Wait()
+		close(got)
+
+		var values []int
+		for v := range got {
+			values = append(values, v)
+		}
+		require.Equal([]int{10}, values)
+		require.Zero(c.Len())
+	}
+}
+
+func TestDualMapCachePutReporting(t *testing.T) {
+	require := require.New(t)
+
+	c := NewDualMapCache[int, string](nil)
+	require.True(c.PutReporting(1, "a"))
+	require.False(c.PutReporting(1, "b"))
+	v, ok := c.Get(1)
+	require.True(ok)
+	require.Equal("b", v)
+
+	c.Evict(1)
+	require.True(c.PutReporting(1, "c"))
+}
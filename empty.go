@@ -3,7 +3,10 @@
 
 package cache
 
-var _ Cacher[struct{}, struct{}] = (*Empty[struct{}, struct{}])(nil)
+var (
+	_ Cacher[struct{}, struct{}] = (*Empty[struct{}, struct{}])(nil)
+	_ BoundReporter              = (*Empty[struct{}, struct{}])(nil)
+)
 
 // Empty is a cache that doesn't store anything.
 type Empty[K any, V any] struct{}
@@ -26,3 +29,8 @@ func (*Empty[_, _]) Len() int {
 func (*Empty[_, _]) PortionFilled() float64 {
 	return 0
 }
+
+// Bounded returns true, as Empty never holds more than zero entries.
+func (*Empty[_, _]) Bounded() bool {
+	return true
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// HotKey is a key and its estimated number of accesses.
+type HotKey[K comparable] struct {
+	Key   K
+	Count uint64
+}
+
+// HotKeyTracker estimates the most accessed keys from a sample of accesses.
+//
+// One in every sampleRate accesses is counted with the Space-Saving
+// algorithm, which keeps counters for at most capacity keys. When a new key
+// is sampled once every counter is taken, it replaces the key with the lowest
+// count and inherits that count, so counts are overestimated by at most the
+// lowest count. Any key accounting for more than 1/capacity of the sampled
+// accesses is guaranteed to be tracked. Counts are scaled back up by
+// sampleRate, so they estimate the total number of accesses.
+type HotKeyTracker[K comparable] struct {
+	rate     uint64
+	capacity int
+	// accesses counts every access, sampled or not.
+	accesses uint64
+
+	mu     sync.Mutex
+	counts map[K]uint64
+}
+
+// NewHotKeyTracker returns a tracker that keeps counters for up to capacity
+// keys and samples one in every sampleRate accesses. Values below one are
+// treated as one.
+func NewHotKeyTracker[K comparable](capacity, sampleRate int) *HotKeyTracker[K] {
+	capacity = max(capacity, 1)
+	return &HotKeyTracker[K]{
+		rate:     uint64(max(sampleRate, 1)),
+		capacity: capacity,
+		counts:   make(map[K]uint64, capacity),
+	}
+}
+
+// Sampled counts an access and reports whether it is sampled, in which case
+// the caller should pass its key to Add. Splitting the two lets callers avoid
+// building the key of accesses that are not sampled.
+func (t *HotKeyTracker[K]) Sampled() bool {
+	return atomic.AddUint64(&t.accesses, 1)%t.rate == 0
+}
+
+// Observe records an access of key, counting it if it is sampled.
+func (t *HotKeyTracker[K]) Observe(key K) {
+	if t.Sampled() {
+		t.Add(key)
+	}
+}
+
+// Add counts a sampled access of key.
+func (t *HotKeyTracker[K]) Add(key K) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if count, ok := t.counts[key]; ok || len(t.counts) < t.capacity {
+		t.counts[key] = count + 1
+		return
+	}
+
+	var (
+		minKey   K
+		minCount uint64
+		first    = true
+	)
+	for k, count := range t.counts {
+		if first || count < minCount {
+			minKey, minCount, first = k, count, false
+		}
+	}
+	delete(t.counts, minKey)
+	t.counts[key] = minCount + 1
+}
+
+// Top returns up to n tracked keys, sorted by decreasing estimated count.
+func (t *HotKeyTracker[K]) Top(n int) []HotKey[K] {
+	t.mu.Lock()
+	hot := make([]HotKey[K], 0, len(t.counts))
+	for key, count := range t.counts {
+		hot = append(hot, HotKey[K]{Key: key, Count: count * t.rate})
+	}
+	t.mu.Unlock()
+
+	return TopHotKeys(hot, n)
+}
+
+// Reset forgets every tracked key.
+func (t *HotKeyTracker[K]) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	clear(t.counts)
+}
+
+// TopHotKeys sorts hot by decreasing count and returns its first n entries.
+// It is useful to merge the results of several trackers.
+func TopHotKeys[K comparable](hot []HotKey[K], n int) []HotKey[K] {
+	slices.SortFunc(hot, func(a, b HotKey[K]) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	return hot[:min(max(n, 0), len(hot))]
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHotKeyTracker(t *testing.T) {
+	require := require.New(t)
+
+	tracker := NewHotKeyTracker[int](4, 1)
+	r := rand.New(rand.NewSource(0))
+	for i := range 10000 {
+		// Key 0 makes up half of the accesses, the rest are spread over many
+		// keys that each take fewer than 1% of the accesses.
+		key := 0
+		if i%2 == 1 {
+			key = 1 + r.Intn(1000)
+		}
+		tracker.Observe(key)
+	}
+
+	top := tracker.Top(1)
+	require.Len(top, 1)
+	require.Zero(top[0].Key)
+	require.GreaterOrEqual(top[0].Count, uint64(5000))
+	require.Len(tracker.Top(10), 4)
+
+	tracker.Reset()
+	require.Empty(tracker.Top(1))
+}
+
+func TestHotKeyTrackerSampling(t *testing.T) {
+	require := require.New(t)
+
+	tracker := NewHotKeyTracker[string](2, 10)
+	for range 1000 {
+		tracker.Observe("hot")
+	}
+	require.Equal([]HotKey[string]{{Key: "hot", Count: 1000}}, tracker.Top(2))
+}
+
+func TestTopHotKeys(t *testing.T) {
+	hot := []HotKey[int]{{1, 5}, {2, 20}, {3, 10}}
+	require.Equal(t, []HotKey[int]{{2, 20}, {3, 10}}, TopHotKeys(hot, 2))
+	require.Empty(t, TopHotKeys(hot, -1))
+}
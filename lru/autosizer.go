@@ -0,0 +1,205 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/luxfi/cache"
+)
+
+var _ Resizable = (*Cache[struct{}, struct{}])(nil)
+
+// DefaultHitRateTolerance is the default AutoSizerConfig.Tolerance.
+const DefaultHitRateTolerance = 0.02
+
+// Resizable is a cache whose capacity can be tuned from its hit rate.
+type Resizable interface {
+	Stats() CacheStats
+	Resize(size int)
+}
+
+// AutoSizerConfig configures an AutoSizer.
+type AutoSizerConfig struct {
+	// Min and Max bound the capacity chosen by the AutoSizer.
+	Min, Max int
+	// TargetHitRate is the hit rate the AutoSizer tries to reach with the
+	// smallest possible capacity.
+	TargetHitRate float64
+	// Tolerance is how far the hit rate may be from TargetHitRate while still
+	// counting as on target, in which case the capacity is held. It defaults
+	// to DefaultHitRateTolerance.
+	Tolerance float64
+	// MinImprovement is the hit rate increase a grow step must yield for
+	// growing to continue. Below it, growing has diminishing returns and the
+	// capacity stops growing until the workload changes.
+	MinImprovement float64
+	// Step is the number of entries added or removed per evaluation.
+	Step int
+	// Interval is the time between evaluations.
+	Interval time.Duration
+	// ReadMemory samples the memory usage before each grow step. It defaults
+	// to cache.ReadRuntimeMemory.
+	ReadMemory func() cache.MemoryUsage
+	// MemoryWatermark is the fraction of the memory limit above which the
+	// capacity isn't grown. It defaults to cache.DefaultLowWatermark, so that
+	// growing stops before a PressureController would start evicting.
+	MemoryWatermark float64
+}
+
+// AutoSizer periodically adjusts the capacity of a cache based on the hit rate
+// observed since the previous evaluation.
+//
+// Below the target hit rate, less Tolerance, the capacity grows by Step until
+// Max is reached or a step stops improving the hit rate by at least
+// MinImprovement. A grow step is skipped while the memory used is above
+// MemoryWatermark of the limit. Above the target, plus Tolerance, the capacity shrinks by
+// Step until Min is reached. If a shrink drops the hit rate below the target,
+// the capacity grows back and shrinks no further. Under a steady workload the
+// capacity thus settles rather than swinging around the target. Once the hit
+// rate moves by more than Tolerance while the capacity is held, the workload
+// is assumed to have changed and these limits are lifted. Evaluations without
+// any Gets leave the capacity untouched.
+type AutoSizer struct {
+	cache  Resizable
+	config AutoSizerConfig
+
+	lastHits   uint64
+	lastMisses uint64
+	lastRate   float64
+	// lastStep is the sign of the previous capacity change, 0 if it was
+	// held.
+	lastStep int
+	// floor and ceiling are the capacities the AutoSizer found not worth
+	// shrinking below or growing above, or 0 if none.
+	floor, ceiling int
+
+	closeOnce sync.Once
+	closing   chan struct{}
+	done      chan struct{}
+}
+
+// NewAutoSizer starts tuning the capacity of c and returns the running
+// AutoSizer. Close must be called to stop it.
+func NewAutoSizer(c Resizable, config AutoSizerConfig) *AutoSizer {
+	config.Min = max(config.Min, 1)
+	config.Max = max(config.Max, config.Min)
+	config.Step = max(config.Step, 1)
+	if config.Tolerance <= 0 {
+		config.Tolerance = DefaultHitRateTolerance
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	if config.ReadMemory == nil {
+		config.ReadMemory = cache.ReadRuntimeMemory
+	}
+	if config.MemoryWatermark <= 0 || config.MemoryWatermark > 1 {
+		config.MemoryWatermark = cache.DefaultLowWatermark
+	}
+
+	stats := c.Stats()
+	a := &AutoSizer{
+		cache:      c,
+		config:     config,
+		lastHits:   stats.Hits,
+		lastMisses: stats.Misses,
+		closing:    make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// Close stops the AutoSizer and waits for its goroutine to exit. The cache
+// keeps its last capacity.
+func (a *AutoSizer) Close() {
+	a.closeOnce.Do(func() {
+		close(a.closing)
+	})
+	<-a.done
+}
+
+func (a *AutoSizer) run() {
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.evaluate()
+		case <-a.closing:
+			return
+		}
+	}
+}
+
+// memoryAvailable reports whether the memory used leaves room to grow.
+func (a *AutoSizer) memoryAvailable() bool {
+	usage := a.config.ReadMemory()
+	return usage.Limit == 0 || float64(usage.Used) < a.config.MemoryWatermark*float64(usage.Limit)
+}
+
+// evaluate performs a single tuning step.
+func (a *AutoSizer) evaluate() {
+	stats := a.cache.Stats()
+	hits := stats.Hits - a.lastHits
+	misses := stats.Misses - a.lastMisses
+	a.lastHits = stats.Hits
+	a.lastMisses = stats.Misses
+	if hits+misses == 0 {
+		return
+	}
+
+	var (
+		rate = float64(hits) / float64(hits+misses)
+		size = stats.Cap
+		step int
+	)
+	if a.lastStep == 0 && math.Abs(rate-a.lastRate) > a.config.Tolerance {
+		// The hit rate changed at a steady capacity, so the workload did.
+		a.floor, a.ceiling = 0, 0
+	}
+	switch {
+	case rate < a.config.TargetHitRate-a.config.Tolerance:
+		switch {
+		case a.lastStep > 0 && rate-a.lastRate < a.config.MinImprovement:
+			// Diminishing returns: growing further won't pay off either.
+			a.ceiling = size
+		case a.lastStep < 0:
+			// The last shrink went too far: undo it and shrink no further.
+			step = 1
+			a.floor = min(size+a.config.Step, a.config.Max)
+		case a.ceiling == 0 || size < a.ceiling:
+			step = 1
+		}
+		if step > 0 && !a.memoryAvailable() {
+			step = 0
+		}
+	case rate > a.config.TargetHitRate+a.config.Tolerance:
+		if size > a.floor {
+			step = -1
+		}
+	}
+	a.lastRate = rate
+
+	newSize := min(max(size+step*a.config.Step, a.config.Min), a.config.Max)
+	if step < 0 {
+		newSize = max(newSize, a.floor)
+	}
+	switch {
+	case newSize > size:
+		a.lastStep = 1
+	case newSize < size:
+		a.lastStep = -1
+	default:
+		a.lastStep = 0
+	}
+	if newSize != size {
+		a.cache.Resize(newSize)
+	}
+}
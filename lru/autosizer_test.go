@@ -0,0 +1,206 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/cache"
+)
+
+// syntheticCache reports a hit rate of capacity/workingSet for every round of
+// workingSet Gets, saturating at 1, or at reusable/workingSet if only reusable
+// of the keys are ever read again.
+type syntheticCache struct {
+	lock       sync.Mutex
+	capacity   int
+	workingSet int
+	reusable   int
+	hits       uint64
+	misses     uint64
+}
+
+func (s *syntheticCache) round() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	hits := min(s.capacity, s.workingSet)
+	if s.reusable > 0 {
+		hits = min(hits, s.reusable)
+	}
+	s.hits += uint64(hits)
+	s.misses += uint64(s.workingSet - hits)
+}
+
+func (s *syntheticCache) Stats() CacheStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return CacheStats{
+		Hits:   s.hits,
+		Misses: s.misses,
+		Cap:    s.capacity,
+	}
+}
+
+func (s *syntheticCache) Resize(size int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.capacity = size
+}
+
+func TestAutoSizerConverges(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial int
+	}{
+		{name: "grow", initial: 100},
+		{name: "shrink", initial: 1000},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			c := &syntheticCache{capacity: test.initial, workingSet: 1000}
+			a := NewAutoSizer(c, AutoSizerConfig{
+				Min:           50,
+				Max:           1000,
+				TargetHitRate: 0.8,
+				Step:          50,
+				Interval:      time.Hour,
+			})
+			defer a.Close()
+
+			for i := 0; i < 100; i++ {
+				c.round()
+				a.evaluate()
+			}
+			require.Equal(800, c.Stats().Cap)
+		})
+	}
+}
+
+func TestAutoSizerDiminishingReturns(t *testing.T) {
+	require := require.New(t)
+
+	// The hit rate saturates at 0.5 regardless of capacity.
+	c := &syntheticCache{capacity: 100, workingSet: 200, reusable: 100}
+	a := NewAutoSizer(c, AutoSizerConfig{
+		Min:            50,
+		Max:            10_000,
+		TargetHitRate:  0.9,
+		MinImprovement: 0.01,
+		Step:           100,
+		Interval:       time.Hour,
+	})
+	defer a.Close()
+
+	// The first grow doesn't improve the hit rate, so growing stops there.
+	for i := 0; i < 100; i++ {
+		c.round()
+		a.evaluate()
+		require.LessOrEqual(c.Stats().Cap, 200)
+	}
+	require.Equal(200, c.Stats().Cap)
+
+	// A change of workload lifts the limit.
+	c.lock.Lock()
+	c.reusable = 0
+	c.lock.Unlock()
+	for i := 0; i < 100; i++ {
+		c.round()
+		a.evaluate()
+	}
+	require.Equal(200, c.Stats().Cap)
+
+	c.lock.Lock()
+	c.workingSet = 1000
+	c.lock.Unlock()
+	for i := 0; i < 100; i++ {
+		c.round()
+		a.evaluate()
+	}
+	require.Equal(900, c.Stats().Cap)
+}
+
+func TestAutoSizerSettles(t *testing.T) {
+	require := require.New(t)
+
+	// No capacity is within the tolerance of the target: 750 falls short and
+	// 850 overshoots.
+	c := &syntheticCache{capacity: 750, workingSet: 1000}
+	a := NewAutoSizer(c, AutoSizerConfig{
+		Min:           50,
+		Max:           1000,
+		TargetHitRate: 0.8,
+		Step:          100,
+		Interval:      time.Hour,
+	})
+	defer a.Close()
+
+	for i := 0; i < 10; i++ {
+		c.round()
+		a.evaluate()
+	}
+	for i := 0; i < 100; i++ {
+		c.round()
+		a.evaluate()
+		require.Equal(850, c.Stats().Cap)
+	}
+}
+
+func TestAutoSizerResizesCache(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCache[int, int](10)
+	a := NewAutoSizer(c, AutoSizerConfig{
+		Min:           5,
+		Max:           20,
+		TargetHitRate: 0.5,
+		Step:          5,
+		Interval:      time.Millisecond,
+	})
+
+	// Only hits, so the cache shrinks to its minimum.
+	c.Put(1, 1)
+	require.Eventually(func() bool {
+		_, _ = c.Get(1)
+		return c.Cap() == 5
+	}, 5*time.Second, time.Millisecond)
+	a.Close()
+	a.Close()
+}
+
+func TestAutoSizerMemoryUnavailable(t *testing.T) {
+	require := require.New(t)
+
+	usage := cache.MemoryUsage{Used: 90, Limit: 100}
+	c := &syntheticCache{capacity: 100, workingSet: 1000}
+	a := NewAutoSizer(c, AutoSizerConfig{
+		Min:           50,
+		Max:           1000,
+		TargetHitRate: 0.8,
+		Step:          100,
+		Interval:      time.Hour,
+		ReadMemory:    func() cache.MemoryUsage { return usage },
+	})
+	defer a.Close()
+
+	// Memory is above the watermark, so the capacity is held.
+	for i := 0; i < 10; i++ {
+		c.round()
+		a.evaluate()
+	}
+	require.Equal(100, c.Stats().Cap)
+
+	// Once memory is freed, growing resumes.
+	usage.Used = 10
+	for i := 0; i < 10; i++ {
+		c.round()
+		a.evaluate()
+	}
+	require.Equal(800, c.Stats().Cap)
+}
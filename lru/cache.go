@@ -1,88 +1,793 @@
 // Copyright (C) 2019-2025, Lux Industries, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
-// Package lru provides the ONE standard LRU cache implementation using container package.
+// Package lru provides the ONE standard LRU cache implementation.
 package lru
 
 import (
+	"container/list"
+	"errors"
+	"fmt"
+	"iter"
+	"math/rand/v2"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/luxfi/cache"
-	"github.com/luxfi/container"
 )
 
+// ErrLengthMismatch is returned when parallel key and value slices have
+// different lengths.
+var ErrLengthMismatch = errors.New("lru: keys and values have different lengths")
+
 // Cache is the standard LRU cache - ONE implementation, no duplicates
 type Cache[K comparable, V any] struct {
-	mu             sync.Mutex
-	containerCache container.Cache[K, V] // Uses container package internally
-	capacity       int
-	onEvict        func(K, V)
+	mu     sync.RWMutex
+	items  map[K]*list.Element
+	lru    *list.List // back is the next entry to evict
+	policy Policy
+	// groups maps each frequency to the frontmost element with that frequency
+	// under PolicyLFU.
+	groups map[uint64]*list.Element
+	// decay configures the aging of frequencies under PolicyLFU. accesses
+	// counts the accesses and lastDecay is when frequencies were last halved.
+	decay     LFUDecay
+	accesses  int
+	lastDecay time.Time
+
+	onEvict func(K, V)
+	veto    func(K, V) bool
+	now     func() time.Time
+
+	frozen     bool
+	freezeOpts FreezeOptions
+	// sealed, once set by Seal, holds every entry in place of items.
+	sealed atomic.Pointer[map[K]V]
+
+	// pinned is the number of pinned entries.
+	pinned int
+
+	// evicted holds entries removed while the lock is held. Their eviction
+	// callbacks are invoked by unlock after the lock has been released.
+	evicted []*entry[K, V]
+
+	// observeAge, if set, is called with the time since last access of every
+	// entry evicted for capacity. It calls both onEvictionAge, set by
+	// Options.OnEvictionAge, and the observer set by SetEvictionAgeObserver.
+	observeAge    func(time.Duration)
+	onEvictionAge func(time.Duration)
+
+	thresholds thresholds
+
+	// thrash counts the recent puts and their evictions, which are checked
+	// against thrashThresholds.
+	thrash           thrashWindow
+	thrashThresholds thresholds
+
+	// subscribers is replaced, never modified in place. events holds the
+	// events of mutations made while the lock is held, delivered by unlock.
+	subscribers []*subscriber[K, V]
+	events      []Event[K, V]
+
+	// priority, if set, chooses among the entries closest to eviction.
+	priority func(K, V) int
+	// weight, if set, draws the victim among the entries closest to eviction
+	// using rng. tick counts accesses, dating the last one of every entry.
+	weight func(K, V) float64
+	rng    *rand.Rand
+	tick   uint64
+
+	// clone, if set, copies values on their way in and out of the cache.
+	clone func(V) V
+	// noPromoteOnWrite leaves the recency order unchanged on updates.
+	noPromoteOnWrite bool
+
+	// deterministic is set by NewDeterministic.
+	deterministic bool
+	// seq is the sequence number of the last inserted entry.
+	seq uint64
+	// epoch is the current epoch. Entries stored in an older one are stale.
+	epoch uint64
+
+	// hot, if non-nil, tracks the most accessed keys.
+	hot *cache.HotKeyTracker[K]
+
+	// reads, if non-nil, holds the accesses of lazy Gets not yet applied.
+	reads *readBuffer
+
+	// calls holds the in-flight computations of GetOrCompute by key.
+	calls map[K]*call[V]
+
+	// length mirrors len(items) and capacity is the maximum number of entries.
+	// Both are only written with the lock held, but are accessed atomically
+	// so that Len, Cap and PortionFilled don't need the lock.
+	length   int64
+	capacity int64
+	// minSize is the smallest capacity: 1, or 0 if a cache may be disabled.
+	minSize int
+
+	// Counters are updated atomically so that Stats doesn't need the lock.
+	hits      uint64
+	misses    uint64
+	evictions uint64
+
+	computes      uint64
+	coalesced     uint64
+	computeErrors uint64
+}
+
+type entry[K comparable, V any] struct {
+	key      K
+	value    V
+	inserted time.Time
+	hits     uint64
+	version  uint64
+	// accessed is when the entry was last read or written. It is only
+	// maintained while an eviction age observer is set.
+	accessed time.Time
+	// pinned excludes the entry from eviction to make room.
+	pinned bool
+	// freq is the number of accesses, only maintained under PolicyLFU.
+	freq uint64
+	// expiry is when the entry stops being returned. The zero value means the
+	// entry never expires.
+	expiry time.Time
+	// seq is the insertion sequence number, ordering InsertionOrder.
+	seq uint64
+	// epoch is the epoch of the cache when the entry was stored.
+	epoch uint64
+	// used is the cache's tick at the last access, only maintained while
+	// eviction is weighted.
+	used uint64
+}
+
+// Info describes the metadata tracked for a cached entry.
+type Info struct {
+	// Inserted is when the key was added to the cache. Updating the value of
+	// an existing key does not change it.
+	Inserted time.Time
+	// Hits is the number of times the entry has been returned by Get.
+	Hits uint64
+	// Position is the entry's index in recency order, where 0 is the most
+	// recently used entry and Len()-1 is the next to be evicted.
+	Position int
+}
+
+// Entry is a key-value pair exported from a cache.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// CacheStats is a snapshot of the cache's counters.
+type CacheStats struct {
+	// Hits is the number of Get calls that found their key.
+	Hits uint64
+	// Misses is the number of Get calls that did not find their key.
+	Misses uint64
+	// Evictions is the number of entries removed to make room for new ones.
+	// Explicit removals are not counted.
+	Evictions uint64
+	// Len is the number of entries in the cache.
+	Len int
+	// Cap is the maximum number of entries in the cache.
+	Cap int
+
+	// Computes is the number of computations started by GetOrCompute.
+	Computes uint64
+	// Coalesced is the number of GetOrCompute calls that waited for a
+	// computation already in flight instead of starting their own.
+	Coalesced uint64
+	// ComputeErrors is the number of computations that returned an error or
+	// panicked.
+	ComputeErrors uint64
+}
+
+// Standard converts s to the [cache.Stats] shape shared by all caches.
+func (s CacheStats) Standard() cache.Stats {
+	portion := 0.0
+	if s.Cap > 0 {
+		portion = float64(s.Len) / float64(s.Cap)
+	}
+	return cache.Stats{
+		Hits:          int64(s.Hits),
+		Misses:        int64(s.Misses),
+		Evictions:     int64(s.Evictions),
+		Len:           s.Len,
+		Cap:           s.Cap,
+		PortionFilled: portion,
+	}
+}
+
+// FreezeOptions configures the behavior of a frozen cache.
+type FreezeOptions struct {
+	// PanicOnWrite makes mutations of a frozen cache panic rather than being
+	// silently ignored. This is useful to surface accidental writes in tests
+	// and debug builds.
+	PanicOnWrite bool
+	// FreezeOrder stops Get from updating the recency order once frozen.
+	FreezeOrder bool
+}
+
+// Options configures a Cache created by NewCacheWithOptions.
+type Options[K comparable, V any] struct {
+	// Size is the maximum number of entries. Values <= 0 are treated as 1,
+	// unless AllowZeroSize is set.
+	Size int
+	// AllowZeroSize makes a Size <= 0 disable the cache, as described by
+	// NewCacheOrDisabled, instead of treating it as 1.
+	AllowZeroSize bool
+	// OnEvict, if set, is invoked as described by NewCacheWithOnEvict.
+	OnEvict func(K, V)
+	// OnEvictVeto, if set, is asked before each entry is evicted to make room
+	// and may return true to give it a second chance: the entry is promoted
+	// as if it had been read and eviction moves on to the next candidate. To
+	// bound the work when every entry vetoes, an eviction that has been vetoed
+	// once per entry in the cache evicts its next candidate regardless.
+	// OnEvictVeto is called with the lock held and must not call back into
+	// the cache. Explicit removals and expirations cannot be vetoed.
+	OnEvictVeto func(K, V) bool
+	// InitialCapacity pre-sizes the internal map to avoid rehashing while a
+	// cache that is expected to become large warms up. It is capped at Size.
+	InitialCapacity int
+	// OnEvictionAge, if set, is called with the time since the last access of
+	// each entry evicted to make room, as described by SetEvictionAgeObserver.
+	// It stays active whatever observer SetEvictionAgeObserver sets.
+	OnEvictionAge func(age time.Duration)
+	// Policy selects the eviction policy. It defaults to PolicyLRU.
+	Policy Policy
+	// Decay ages the frequencies of PolicyLFU. It is ignored by other
+	// policies.
+	Decay LFUDecay
+	// LazyPromotion makes Get take only a read lock, so that concurrent reads
+	// don't serialize. Each read is recorded in a fixed-size buffer and
+	// applied to the recency order, hit counts and access times the next time
+	// the cache is written to, or once the buffer fills. Until then, eviction
+	// and the order reported by All, Drain and EntryInfo don't reflect the
+	// reads. Reads arriving while the buffer is full are dropped, so under
+	// heavy read traffic some accesses never promote their entry. Other
+	// lookups, such as Contains and GetOrdered, still take the write lock.
+	LazyPromotion bool
+	// NoPromoteOnWrite makes writes to an existing key update its value in
+	// place, so that the recency order only reflects reads. By default, Put
+	// and the other methods storing a value, such as PutWithTTL,
+	// PutVersioned, CompareAndSwap and Merge, mark an existing entry as most
+	// recently used, or count as an access under PolicyLFU. With this option
+	// they leave its position and access time unchanged, so an entry that is
+	// written often but never read is evicted as if it were never written.
+	// Newly inserted entries are still placed as most recently used, and
+	// Touch still promotes.
+	NoPromoteOnWrite bool
+	// Clone, if set, copies values as described by NewCacheWithClone.
+	Clone func(V) V
+	// Priority, if set, steers evictions as described by NewPriorityCache.
+	Priority func(K, V) int
+	// Weight, if set, randomizes evictions as described by NewWeightedCache.
+	// Priority takes precedence over it.
+	Weight func(K, V) float64
+	// Deterministic makes the cache break ties as described by
+	// NewDeterministic. It overrides LazyPromotion.
+	Deterministic bool
+	// HotKeySampleRate, if positive, tracks the most accessed keys, reported
+	// by HotKeys, from one in every HotKeySampleRate calls to Get and Put.
+	// Tracking is off by default.
+	HotKeySampleRate int
+	// Clock returns the current time. It defaults to time.Now and can be
+	// replaced to control expiry in tests.
+	Clock func() time.Time
 }
 
 // NewCache creates a new LRU cache - THE standard way
 func NewCache[K comparable, V any](size int) *Cache[K, V] {
-	if size <= 0 {
-		size = 1
+	return NewCacheWithOnEvict[K, V](size, nil)
+}
+
+// NewCacheOrDisabled creates a cache like NewCache, except that a size <= 0
+// disables it instead of being treated as 1. A disabled cache stores nothing:
+// every Put is dropped and every Get misses, as with cache.Empty, so a single
+// size setting of 0 can turn caching off. Resize can enable it later.
+func NewCacheOrDisabled[K comparable, V any](size int) *Cache[K, V] {
+	return NewCacheWithOptions(Options[K, V]{
+		Size:          size,
+		AllowZeroSize: true,
+	})
+}
+
+// NewCacheWithOptions creates a new LRU cache configured by opts.
+func NewCacheWithOptions[K comparable, V any](opts Options[K, V]) *Cache[K, V] {
+	minSize := 1
+	if opts.AllowZeroSize {
+		minSize = 0
 	}
-	return &Cache[K, V]{
-		containerCache: container.NewLRUCache[K, V](size),
-		capacity:       size,
-		onEvict:        nil,
+	size := max(opts.Size, minSize)
+	now := opts.Clock
+	if now == nil {
+		now = time.Now
 	}
-}
+	c := &Cache[K, V]{
+		items:    make(map[K]*list.Element, min(max(opts.InitialCapacity, 0), size)),
+		lru:      list.New(),
+		capacity: int64(size),
+		minSize:  minSize,
+		onEvict:  opts.OnEvict,
+		veto:     opts.OnEvictVeto,
+		clone:    opts.Clone,
+		priority: opts.Priority,
+		weight:   opts.Weight,
+		now:      now,
+		policy:   opts.Policy,
 
-// NewCacheWithOnEvict creates cache with eviction callback
-func NewCacheWithOnEvict[K comparable, V any](size int, onEvict func(K, V)) *Cache[K, V] {
-	if size <= 0 {
-		size = 1
+		deterministic:    opts.Deterministic,
+		noPromoteOnWrite: opts.NoPromoteOnWrite,
+
+		observeAge:    opts.OnEvictionAge,
+		onEvictionAge: opts.OnEvictionAge,
+	}
+	if c.policy == PolicyLFU {
+		c.groups = make(map[uint64]*list.Element)
+		c.decay = opts.Decay
+		c.lastDecay = now()
+	}
+	if opts.LazyPromotion && !opts.Deterministic {
+		c.reads = &readBuffer{}
 	}
-	return &Cache[K, V]{
-		containerCache: container.NewLRUCacheWithOnEvict[K, V](size, onEvict),
-		capacity:       size,
-		onEvict:        onEvict,
+	if opts.Weight != nil {
+		c.rng = newWeightedRand(opts.Deterministic)
+	}
+	if opts.HotKeySampleRate > 0 {
+		c.hot = cache.NewHotKeyTracker[K](HotKeyCapacity, opts.HotKeySampleRate)
+	}
+	return c
+}
+
+// NewCacheWithClone creates a cache that copies values with clone, so that
+// callers can't mutate the cache's copy through a value they passed in or got
+// back. Every value stored by Put and its variants is cloned, as is every
+// value returned by Get, GetOrDefault, GetOrZero, GetOrdered, GetVersioned,
+// GetOrCompute and All. Each of these calls thus pays for a clone, those
+// storing a value while holding the lock. Values passed to eviction callbacks,
+// subscribers and predicates are the cache's own copies and must not be
+// mutated.
+func NewCacheWithClone[K comparable, V any](size int, clone func(V) V) *Cache[K, V] {
+	return NewCacheWithOptions(Options[K, V]{
+		Size:  size,
+		Clone: clone,
+	})
+}
+
+// cloned returns the copy of value handed out to callers.
+func (c *Cache[K, V]) cloned(value V) V {
+	if c.clone == nil {
+		return value
 	}
+	return c.clone(value)
+}
+
+// NewCacheWithOnEvict creates cache with eviction callback.
+//
+// onEvict is also invoked for expired entries when they are removed. It is
+// never invoked while the cache's lock is held, so it may call
+// back into the cache. Callbacks run on the goroutine whose operation removed
+// the entries, in removal order, before that operation returns. Other
+// goroutines may observe the entries as already removed before the callbacks
+// have run.
+func NewCacheWithOnEvict[K comparable, V any](size int, onEvict func(K, V)) *Cache[K, V] {
+	return NewCacheWithOptions(Options[K, V]{
+		Size:    size,
+		OnEvict: onEvict,
+	})
 }
 
 // Get retrieves value from cache
 func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
+	if c.hot != nil {
+		c.hot.Observe(key)
+	}
+	if sealed := c.sealedEntries(); sealed != nil {
+		value, ok = sealed[key]
+	} else if c.reads != nil {
+		value, ok = c.getLazy(key)
+	} else {
+		c.mu.Lock()
+		value, ok = c.get(key)
+		c.unlock()
+	}
+
+	if ok {
+		atomic.AddUint64(&c.hits, 1)
+		value = c.cloned(value)
+	} else {
+		atomic.AddUint64(&c.misses, 1)
+	}
+	return value, ok
+}
+
+// GetOrDefault returns the value of key, or def if the key is missing. A hit
+// marks the entry as most recently used, like Get.
+func (c *Cache[K, V]) GetOrDefault(key K, def V) V {
+	if value, ok := c.Get(key); ok {
+		return value
+	}
+	return def
+}
+
+// GetOrZero returns the value of key, or the zero value if the key is missing.
+func (c *Cache[K, V]) GetOrZero(key K) V {
+	value, _ := c.Get(key)
+	return value
+}
+
+// GetOrdered looks up every key under a single lock acquisition. values[i]
+// and found[i] are the result of looking up keys[i], with values[i] left as
+// the zero value on a miss. Hits are promoted in slice order, so the last hit
+// ends up most recently used.
+func (c *Cache[K, V]) GetOrdered(keys []K) (values []V, found []bool) {
+	values = make([]V, len(keys))
+	found = make([]bool, len(keys))
+
+	var hits uint64
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.containerCache.Get(key)
+	for i, key := range keys {
+		values[i], found[i] = c.get(key)
+		if found[i] {
+			hits++
+		}
+	}
+	c.unlock()
+
+	atomic.AddUint64(&c.hits, hits)
+	atomic.AddUint64(&c.misses, uint64(len(keys))-hits)
+	if c.clone != nil {
+		for i := range values {
+			if found[i] {
+				values[i] = c.clone(values[i])
+			}
+		}
+	}
+	return values, found
 }
 
 // Put adds value to cache
 func (c *Cache[K, V]) Put(key K, value V) {
+	if c.hot != nil {
+		c.hot.Observe(key)
+	}
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.containerCache.Put(key, value)
+	defer c.unlock()
+	if c.rejectWrite() {
+		return
+	}
+	c.put(key, value)
+}
+
+// PutOrdered puts keys[i] with values[i] in slice order under a single lock
+// acquisition, so the last entries are the most recently used and the ones
+// that survive if more entries than the capacity are given. This makes bulk
+// loads reproducible. Nothing is stored if the slices have different lengths.
+func (c *Cache[K, V]) PutOrdered(keys []K, values []V) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("%w: %d keys, %d values", ErrLengthMismatch, len(keys), len(values))
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+	if c.rejectWrite() {
+		return nil
+	}
+	for i, key := range keys {
+		c.put(key, values[i])
+	}
+	return nil
+}
+
+// PutReporting is like Put, but also reports whether it inserted a new entry,
+// rather than updating an existing one, as a single atomic step. It returns
+// false if the value wasn't stored at all, as in a frozen cache or a full one
+// whose entries are all pinned, so true means the key was added. An expired
+// entry not yet removed is updated, while one from an older epoch is evicted
+// and replaced by a new entry.
+func (c *Cache[K, V]) PutReporting(key K, value V) (inserted bool) {
+	if c.hot != nil {
+		c.hot.Observe(key)
+	}
+	c.mu.Lock()
+	defer c.unlock()
+	if c.rejectWrite() {
+		return false
+	}
+	elem, existed := c.items[key]
+	existed = existed && !c.stale(elem.Value.(*entry[K, V]))
+	c.put(key, value)
+	_, stored := c.items[key]
+	return stored && !existed
+}
+
+// PutEvicting is like Put, but also returns the key of the least recently
+// used entry if one was evicted to make room. Updating an existing key never
+// evicts. The eviction callback, if any, is invoked as usual.
+func (c *Cache[K, V]) PutEvicting(key K, value V) (evictedKey K, evicted bool) {
+	c.mu.Lock()
+	defer c.unlock()
+	if c.rejectWrite() {
+		return evictedKey, false
+	}
+	return c.put(key, value)
+}
+
+// PutExpireAt adds value to the cache until the wall-clock instant t. Get
+// treats the entry as missing once the current time is at or after t. Expired
+// entries are removed lazily when looked up, so they still count towards Len
+// and may be evicted like any other entry until then.
+//
+// A later Put of the same key clears the expiry.
+func (c *Cache[K, V]) PutExpireAt(key K, value V, t time.Time) {
+	c.mu.Lock()
+	defer c.unlock()
+	if c.rejectWrite() {
+		return
+	}
+	c.put(key, value)
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*entry[K, V]).expiry = t
+	}
+}
+
+// PutWithTTL adds value to the cache for the duration ttl, measured by the
+// cache's clock. See PutExpireAt.
+func (c *Cache[K, V]) PutWithTTL(key K, value V, ttl time.Duration) {
+	c.PutExpireAt(key, value, c.now().Add(ttl))
+}
+
+// PutVersioned stores value only if version is greater than the version of
+// the currently cached value, returning whether it was stored. An absent key is
+// always stored. Values stored with Put have version 0.
+//
+// Versions are forgotten when an entry is removed, so a stale writer can
+// succeed after the key has been evicted.
+func (c *Cache[K, V]) PutVersioned(key K, value V, version uint64) bool {
+	c.mu.Lock()
+	defer c.unlock()
+	if c.rejectWrite() {
+		return false
+	}
+	if elem, ok := c.items[key]; ok && !c.stale(elem.Value.(*entry[K, V])) && version <= elem.Value.(*entry[K, V]).version {
+		return false
+	}
+	c.put(key, value)
+	elem, ok := c.items[key]
+	if ok {
+		elem.Value.(*entry[K, V]).version = version
+	}
+	return ok
+}
+
+// GetVersioned is like Get, but also returns the version of the value.
+func (c *Cache[K, V]) GetVersioned(key K) (V, uint64, bool) {
+	c.mu.Lock()
+	value, ok := c.get(key)
+	var version uint64
+	if elem, found := c.items[key]; ok && found {
+		version = elem.Value.(*entry[K, V]).version
+	}
+	c.unlock()
+
+	if ok {
+		atomic.AddUint64(&c.hits, 1)
+		value = c.cloned(value)
+	} else {
+		atomic.AddUint64(&c.misses, 1)
+	}
+	return value, version, ok
+}
+
+// CompareAndSwap replaces the value of key with new if its current value is
+// equal to old according to eq, returning whether the swap happened. A missing
+// key is never swapped. Like Put, a successful swap marks the entry as most
+// recently used. eq is called with the lock held and must not call back into
+// the cache.
+func (c *Cache[K, V]) CompareAndSwap(key K, old, new V, eq func(V, V) bool) bool {
+	c.mu.Lock()
+	defer c.unlock()
+	if c.rejectWrite() {
+		return false
+	}
+	elem, ok := c.items[key]
+	if !ok || c.stale(elem.Value.(*entry[K, V])) || !eq(elem.Value.(*entry[K, V]).value, old) {
+		return false
+	}
+	c.put(key, new)
+	return true
+}
+
+// Merge stores combine(old, value) if the key holds old, or value if the key is
+// missing, as a single atomic step, so that concurrent writers accumulating
+// into the same key don't lose updates. Like Put, it marks the entry as most
+// recently used. combine is called with the lock held and must not call back
+// into the cache. An expired entry counts as missing.
+func (c *Cache[K, V]) Merge(key K, value V, combine func(old, new V) V) {
+	c.mu.Lock()
+	defer c.unlock()
+	if c.rejectWrite() {
+		return
+	}
+	if elem, ok := c.lookup(key); ok {
+		value = combine(elem.Value.(*entry[K, V]).value, value)
+	}
+	c.put(key, value)
+}
+
+// Touch marks the entry with the key as most recently used without reading
+// its value, returning whether the key exists.
+func (c *Cache[K, V]) Touch(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.lookup(key)
+	if !ok {
+		return false
+	}
+	if !c.frozen || !c.freezeOpts.FreezeOrder {
+		c.promote(elem)
+	}
+	c.markAccessed(elem.Value.(*entry[K, V]))
+	return true
+}
+
+// Pin excludes the entry with the key from eviction, returning whether the
+// key exists. A pinned entry still counts towards the capacity, so pinning
+// shrinks the room left for other entries, and it is still removed by
+// explicit removals such as Delete, Clear or EvictFunc, and by expiry.
+//
+// If the cache is full and every entry is pinned, Put of a new key is
+// rejected: the value is not stored and nothing is evicted. Evictions skip
+// over pinned entries one by one, so pinning is meant for a small number of
+// keys.
+func (c *Cache[K, V]) Pin(key K) bool {
+	return c.setPinned(key, true)
+}
+
+// Unpin makes the entry with the key evictable again, returning whether the
+// key exists.
+func (c *Cache[K, V]) Unpin(key K) bool {
+	return c.setPinned(key, false)
+}
+
+func (c *Cache[K, V]) setPinned(key K, pinned bool) bool {
+	c.mu.Lock()
+	defer c.unlock()
+	if c.rejectWrite() {
+		return false
+	}
+
+	elem, ok := c.lookup(key)
+	if !ok {
+		return false
+	}
+	ent := elem.Value.(*entry[K, V])
+	switch {
+	case pinned && !ent.pinned:
+		c.pinned++
+	case !pinned && ent.pinned:
+		c.pinned--
+	}
+	ent.pinned = pinned
+	return true
 }
 
 // Delete removes value from cache
 func (c *Cache[K, V]) Delete(key K) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.containerCache.Delete(key)
+	defer c.unlock()
+	if c.rejectWrite() {
+		return
+	}
+	if elem, ok := c.items[key]; ok {
+		c.evictElement(elem)
+	}
 }
 
-// Len returns cache size
-func (c *Cache[K, V]) Len() int {
+// DelGet removes key and returns its value, reporting whether it was present.
+// The lookup and removal happen under a single lock acquisition, so of several
+// goroutines calling DelGet for the same key only one receives the value. The
+// eviction callback is invoked as for Delete. A frozen cache removes nothing
+// and reports every key as missing.
+func (c *Cache[K, V]) DelGet(key K) (V, bool) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.containerCache.Len()
+	defer c.unlock()
+	var zero V
+	if c.rejectWrite() {
+		return zero, false
+	}
+	elem, ok := c.lookup(key)
+	if !ok {
+		return zero, false
+	}
+	value := elem.Value.(*entry[K, V]).value
+	c.evictElement(elem)
+	return value, true
 }
 
-// Clear removes all items
+// EvictMany removes every listed key under a single lock acquisition and
+// returns the number of entries removed. Missing and repeated keys are
+// ignored. The eviction callback is invoked for each removed entry.
+func (c *Cache[K, V]) EvictMany(keys []K) int {
+	c.mu.Lock()
+	defer c.unlock()
+	if c.rejectWrite() {
+		return 0
+	}
+
+	removed := 0
+	for _, key := range keys {
+		if elem, ok := c.items[key]; ok {
+			c.evictElement(elem)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len returns cache size. It doesn't acquire the lock.
+func (c *Cache[K, V]) Len() int {
+	return int(atomic.LoadInt64(&c.length))
+}
+
+// Clear removes all items. The eviction callback is invoked for every removed
+// entry, from least to most recently used, after the cache has been emptied
+// and its lock released.
 func (c *Cache[K, V]) Clear() {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.containerCache = container.NewLRUCache[K, V](c.capacity)
+	defer c.unlock()
+	if c.rejectWrite() {
+		return
+	}
+	if c.onEvict != nil {
+		for e := c.lru.Back(); e != nil; e = e.Prev() {
+			c.evicted = append(c.evicted, e.Value.(*entry[K, V]))
+		}
+	}
+	c.items = make(map[K]*list.Element)
+	atomic.StoreInt64(&c.length, 0)
+	c.pinned = 0
+	c.resetOrder()
+	c.emitFlush()
+}
+
+// Drain removes all entries and returns them from most to least recently
+// used, under a single lock acquisition, so that they can be persisted and
+// later reinserted in the same priority. The eviction callback is invoked for
+// every entry as by Clear. A frozen cache is not drained.
+func (c *Cache[K, V]) Drain() []Entry[K, V] {
+	c.mu.Lock()
+	defer c.unlock()
+	if c.rejectWrite() {
+		return nil
+	}
+
+	entries := make([]Entry[K, V], 0, len(c.items))
+	for e := c.lru.Front(); e != nil; e = e.Next() {
+		ent := e.Value.(*entry[K, V])
+		entries = append(entries, Entry[K, V]{Key: ent.key, Value: ent.value})
+	}
+	if c.onEvict != nil {
+		for e := c.lru.Back(); e != nil; e = e.Prev() {
+			c.evicted = append(c.evicted, e.Value.(*entry[K, V]))
+		}
+	}
+	c.items = make(map[K]*list.Element)
+	atomic.StoreInt64(&c.length, 0)
+	c.pinned = 0
+	c.resetOrder()
+	c.emitFlush()
+	return entries
 }
 
 // Contains checks key existence
 func (c *Cache[K, V]) Contains(key K) bool {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	_, ok := c.containerCache.Get(key)
+	defer c.unlock()
+	_, ok := c.get(key)
 	return ok
 }
 
@@ -101,17 +806,565 @@ func (c *Cache[K, V]) Flush() {
 	c.Clear()
 }
 
-// PortionFilled returns fraction of cache currently filled (0 --> 1)
-func (c *Cache[K, V]) PortionFilled() float64 {
+// FlushColdest evicts the least recently used fraction of the entries, rounded
+// down, and returns the number removed. fraction is clamped to [0, 1], so 0.5
+// drops the cold half and 1 behaves like Flush. Entries are chosen and
+// reported to the eviction callback as when making room for a Put, so pinned
+// entries are kept and OnEvictVeto is consulted.
+func (c *Cache[K, V]) FlushColdest(fraction float64) int {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	current := float64(c.containerCache.Len())
-	capacity := float64(c.capacity)
+	defer c.unlock()
+	if c.rejectWrite() {
+		return 0
+	}
+	return c.evictOldest(int(float64(len(c.items)) * min(max(fraction, 0), 1)))
+}
+
+// EvictOldest evicts up to n entries in the order they would be evicted to
+// make room for a Put, as FlushColdest does, and returns the number removed.
+func (c *Cache[K, V]) EvictOldest(n int) int {
+	c.mu.Lock()
+	defer c.unlock()
+	if c.rejectWrite() {
+		return 0
+	}
+	return c.evictOldest(n)
+}
+
+// evictOldest evicts up to n entries to make room. Must be called with the
+// lock held.
+func (c *Cache[K, V]) evictOldest(n int) int {
+	removed := 0
+	for ; removed < n; removed++ {
+		if _, ok := c.evictLRU(); !ok {
+			break
+		}
+	}
+	return removed
+}
+
+// PortionFilled returns fraction of cache currently filled (0 --> 1). It
+// doesn't acquire the lock, so it can be polled without slowing down other
+// operations.
+func (c *Cache[K, V]) PortionFilled() float64 {
+	current := float64(atomic.LoadInt64(&c.length))
+	capacity := float64(atomic.LoadInt64(&c.capacity))
 	if capacity == 0 {
 		return 0
 	}
 	return current / capacity
 }
 
+// Resize changes the maximum number of entries. Values <= 0 are treated as 1,
+// or disable the cache if it was created with Options.AllowZeroSize.
+// If the cache holds more entries than the new size, the least recently used
+// entries are evicted.
+func (c *Cache[K, V]) Resize(size int) {
+	c.mu.Lock()
+	defer c.unlock()
+	if c.rejectWrite() {
+		return
+	}
+	capacity := max(size, c.minSize)
+	atomic.StoreInt64(&c.capacity, int64(capacity))
+	for len(c.items) > capacity {
+		if _, ok := c.evictLRU(); !ok {
+			break
+		}
+	}
+}
+
+// HotKeyCapacity is the number of keys tracked by a cache created with
+// Options.HotKeySampleRate set.
+const HotKeyCapacity = 64
+
+// HotKeys returns up to n of the most accessed keys with their estimated
+// number of Get and Put calls, most accessed first, as estimated by
+// [cache.HotKeyTracker]. It returns nil unless the cache was created with
+// Options.HotKeySampleRate set.
+func (c *Cache[K, V]) HotKeys(n int) []cache.HotKey[K] {
+	if c.hot == nil {
+		return nil
+	}
+	return c.hot.Top(n)
+}
+
+// Bounded returns true, as the cache holds at most Cap entries.
+func (*Cache[_, _]) Bounded() bool {
+	return true
+}
+
+// Compact rebuilds the cache's internal map at a size proportional to its
+// current number of entries. Go maps never shrink, so a long-lived cache that
+// once held many more entries than it does now keeps the memory of its peak;
+// Compact releases it. The contents and order of the cache are unchanged. It
+// allocates a new map and copies every entry, so it should be called
+// occasionally, for example after a large EvictFunc or Resize, rather than
+// after every removal.
+func (c *Cache[K, V]) Compact() {
+	c.mu.Lock()
+	defer c.unlock()
+	c.items = compactMap(c.items)
+	if c.groups != nil {
+		c.groups = compactMap(c.groups)
+	}
+}
+
+// compactMap returns a copy of m sized for its current length. maps.Clone is
+// not used as it may preserve the capacity of m.
+func compactMap[M ~map[K]V, K comparable, V any](m M) M {
+	compacted := make(M, len(m))
+	for k, v := range m {
+		compacted[k] = v
+	}
+	return compacted
+}
+
+// SetEvictionAgeObserver sets the function called with the time since the last
+// access of each entry evicted to make room for another, replacing any
+// observer previously set by SetEvictionAgeObserver. Options.OnEvictionAge is
+// called too, before observe. Young evictions indicate that the cache is
+// thrashing.
+// Explicit removals and expirations are not observed. Entries are only
+// timestamped while an observer is set, so entries not accessed since then are
+// skipped. observe is called with the lock held and must not call back into
+// the cache.
+func (c *Cache[K, V]) SetEvictionAgeObserver(observe func(age time.Duration)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.observeAge = observe
+	if first := c.onEvictionAge; first != nil && observe != nil {
+		c.observeAge = func(age time.Duration) {
+			first(age)
+			observe(age)
+		}
+	} else if first != nil {
+		c.observeAge = first
+	}
+}
+
+// Cap returns the maximum number of entries. It doesn't acquire the lock.
+func (c *Cache[K, V]) Cap() int {
+	return int(atomic.LoadInt64(&c.capacity))
+}
+
+// OnThreshold registers fn to be called when the fill ratio of the cache, as
+// reported by PortionFilled, reaches ratio from below. Once fired, the
+// threshold only fires again after the fill ratio has dropped at least 0.05
+// below ratio, so a cache hovering around the ratio doesn't fire repeatedly. A
+// cache that is already filled past ratio fires on its next operation.
+//
+// fn is invoked with the fill ratio observed, after the lock was released, on
+// the goroutine whose operation crossed the threshold.
+func (c *Cache[K, V]) OnThreshold(ratio float64, fn func(current float64)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.thresholds = append(c.thresholds, &threshold{
+		ratio: ratio,
+		fn:    fn,
+		armed: true,
+	})
+}
+
+// Stats returns a snapshot of the cache's counters along with its current
+// length and capacity.
+func (c *Cache[K, V]) Stats() CacheStats {
+	return CacheStats{
+		Hits:      atomic.LoadUint64(&c.hits),
+		Misses:    atomic.LoadUint64(&c.misses),
+		Evictions: atomic.LoadUint64(&c.evictions),
+		Len:       c.Len(),
+		Cap:       c.Cap(),
+
+		Computes:      atomic.LoadUint64(&c.computes),
+		Coalesced:     atomic.LoadUint64(&c.coalesced),
+		ComputeErrors: atomic.LoadUint64(&c.computeErrors),
+	}
+}
+
+// EvictFunc removes every entry for which pred returns true and returns the
+// number of entries removed. The cache is locked once for the whole pass, and
+// the eviction callback is invoked for each removed entry. pred must not call
+// back into the cache.
+func (c *Cache[K, V]) EvictFunc(pred func(K, V) bool) int {
+	c.mu.Lock()
+	defer c.unlock()
+	if c.rejectWrite() {
+		return 0
+	}
+
+	removed := 0
+	for e := c.lru.Front(); e != nil; {
+		next := e.Next()
+		if ent := e.Value.(*entry[K, V]); pred(ent.key, ent.value) {
+			c.evictElement(e)
+			removed++
+		}
+		e = next
+	}
+	return removed
+}
+
+// All returns an iterator over the entries of the cache, from most to least
+// recently used. The entries are snapshotted when iteration starts, so the
+// loop body may safely call back into the cache; iteration does not affect
+// the recency order. A sealed cache yields its entries in no particular order.
+func (c *Cache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if sealed := c.sealedEntries(); sealed != nil {
+			for key, value := range sealed {
+				if !yield(key, c.cloned(value)) {
+					return
+				}
+			}
+			return
+		}
+
+		c.mu.Lock()
+		entries := make([]*entry[K, V], 0, len(c.items))
+		for e := c.lru.Front(); e != nil; e = e.Next() {
+			entries = append(entries, e.Value.(*entry[K, V]))
+		}
+		c.mu.Unlock()
+
+		for _, ent := range entries {
+			if !yield(ent.key, c.cloned(ent.value)) {
+				return
+			}
+		}
+	}
+}
+
+// Oldest returns the entry the cache would evict next to make room, without
+// marking it as used, or false if there is none. For an LRU cache without
+// pinned entries or a priority function this is the least recently used
+// entry. Admission code can compare a candidate against it before deciding
+// whether to Put the candidate.
+func (c *Cache[K, V]) Oldest() (K, V, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+	c.applyReads()
+	return c.entryAt(c.evictionCandidate())
+}
+
+// Newest returns the entry the cache would evict last, without marking it as
+// used, or false if the cache is empty. For an LRU cache this is the most
+// recently used entry.
+func (c *Cache[K, V]) Newest() (K, V, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+	c.applyReads()
+	return c.entryAt(c.lru.Front())
+}
+
+// entryAt returns the key and the caller's copy of the value of elem, or false
+// if elem is nil.
+func (c *Cache[K, V]) entryAt(elem *list.Element) (K, V, bool) {
+	if elem == nil {
+		var (
+			key   K
+			value V
+		)
+		return key, value, false
+	}
+	ent := elem.Value.(*entry[K, V])
+	return ent.key, c.cloned(ent.value), true
+}
+
+// Sample returns up to n keys of the cache selected pseudo-randomly, without
+// affecting the recency order. The selection relies on Go's randomized map
+// iteration order, which is cheap but not uniformly distributed; it is
+// suitable for estimating statistics, not for anything requiring fairness.
+// A deterministic cache returns the n earliest inserted keys instead, unless
+// it is sealed.
+func (c *Cache[K, V]) Sample(n int) []K {
+	if sealed := c.sealedEntries(); sealed != nil {
+		return sampleKeys(sealed, n)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.deterministic {
+		n = min(n, len(c.items))
+		if n <= 0 {
+			return nil
+		}
+		return c.oldestInserted(n)
+	}
+	return sampleKeys(c.items, n)
+}
+
+// sampleKeys returns up to n keys of m in map iteration order.
+func sampleKeys[K comparable, V any](m map[K]V, n int) []K {
+	n = min(n, len(m))
+	if n <= 0 {
+		return nil
+	}
+	keys := make([]K, 0, n)
+	for key := range m {
+		if len(keys) == n {
+			break
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// EntryInfo returns the metadata of the entry with the key, if it exists.
+// Unlike Get, it does not mark the entry as recently used. Computing the
+// position walks the recency list, so this is O(n) and intended for admin
+// and debugging tools rather than hot paths.
+func (c *Cache[K, V]) EntryInfo(key K) (Info, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return Info{}, false
+	}
+	position := 0
+	for e := c.lru.Front(); e != elem; e = e.Next() {
+		position++
+	}
+	ent := elem.Value.(*entry[K, V])
+	return Info{
+		Inserted: ent.inserted,
+		Hits:     ent.hits,
+		Position: position,
+	}, true
+}
+
+// Freeze makes the cache read-only for the rest of its lifetime. After Freeze,
+// Put, Delete, Evict, EvictFunc, Clear and Flush are ignored, while Get keeps
+// working and keeps updating the recency order.
+func (c *Cache[K, V]) Freeze() {
+	c.FreezeWithOptions(FreezeOptions{})
+}
+
+// FreezeWithOptions is like Freeze, but allows mutations to panic and the
+// recency order to be frozen as well.
+func (c *Cache[K, V]) FreezeWithOptions(opts FreezeOptions) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.frozen = true
+	c.freezeOpts = opts
+}
+
+// Frozen reports whether Freeze has been called.
+func (c *Cache[K, V]) Frozen() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.frozen
+}
+
+// rejectWrite reports whether a mutation must be skipped because the cache is
+// frozen, panicking instead if configured to.
+func (c *Cache[K, V]) rejectWrite() bool {
+	if !c.frozen {
+		return false
+	}
+	if c.freezeOpts.PanicOnWrite {
+		panic("lru: write to frozen cache")
+	}
+	return true
+}
+
+// lookup returns the element of key, removing it instead if it has expired or
+// is stale. Such entries of a frozen cache are reported as missing but kept.
+func (c *Cache[K, V]) lookup(key K) (*list.Element, bool) {
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	if c.live(elem.Value.(*entry[K, V])) {
+		return elem, true
+	}
+	if !c.frozen {
+		c.evictElement(elem)
+	}
+	return nil, false
+}
+
+func (c *Cache[K, V]) get(key K) (V, bool) {
+	if sealed := c.sealedEntries(); sealed != nil {
+		value, ok := sealed[key]
+		return value, ok
+	}
+	elem, ok := c.lookup(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if !c.frozen || !c.freezeOpts.FreezeOrder {
+		c.promote(elem)
+	}
+	ent := elem.Value.(*entry[K, V])
+	ent.hits++
+	c.markAccessed(ent)
+	return ent.value, true
+}
+
+// put stores value and returns the key of the entry evicted to make room for
+// it, if any.
+func (c *Cache[K, V]) put(key K, value V) (evictedKey K, evicted bool) {
+	if c.clone != nil {
+		value = c.clone(value)
+	}
+	elem, ok := c.items[key]
+	if ok && c.stale(elem.Value.(*entry[K, V])) {
+		// Entries from an older epoch are replaced rather than updated.
+		c.evictElement(elem)
+		ok = false
+	}
+	if ok {
+		ent := elem.Value.(*entry[K, V])
+		if !c.noPromoteOnWrite {
+			c.promote(elem)
+			c.markAccessed(ent)
+		}
+		ent.value = value
+		ent.version = 0
+		ent.expiry = time.Time{}
+		c.emit(EventPut, key, value)
+		c.thrash.record(false)
+		return evictedKey, false
+	}
+
+	if int64(len(c.items)) >= atomic.LoadInt64(&c.capacity) {
+		if evictedKey, evicted = c.evictLRU(); !evicted {
+			// Every entry is pinned.
+			c.thrash.record(false)
+			return evictedKey, false
+		}
+	}
+
+	now := c.now()
+	ent := &entry[K, V]{
+		key:      key,
+		value:    value,
+		inserted: now,
+		epoch:    c.epoch,
+	}
+	if c.observeAge != nil {
+		ent.accessed = now
+	}
+	c.seq++
+	ent.seq = c.seq
+	c.items[key] = c.insert(ent)
+	atomic.AddInt64(&c.length, 1)
+	c.emit(EventPut, key, value)
+	c.thrash.record(evicted)
+	return evictedKey, evicted
+}
+
+// evictLRU evicts the least recently used unpinned entry to make room and
+// returns its key, or false if there is no such entry.
+func (c *Cache[K, V]) evictLRU() (K, bool) {
+	var (
+		victim *list.Element
+		ent    *entry[K, V]
+	)
+	c.applyReads()
+	for vetoes := 0; ; vetoes++ {
+		victim = c.evictionCandidate()
+		if victim == nil {
+			var zero K
+			return zero, false
+		}
+		ent = victim.Value.(*entry[K, V])
+		if c.veto == nil || vetoes >= len(c.items) || !c.veto(ent.key, ent.value) {
+			break
+		}
+		c.promote(victim)
+		if c.policy == PolicyFIFO {
+			// FIFO ignores accesses, so reinsert the entry explicitly.
+			c.lru.MoveToFront(victim)
+		}
+	}
+
+	if c.observeAge != nil && !ent.accessed.IsZero() {
+		c.observeAge(c.now().Sub(ent.accessed))
+	}
+	c.evictElement(victim)
+	atomic.AddUint64(&c.evictions, 1)
+	return ent.key, true
+}
+
+// evictionCandidate returns the element evictLRU would remove next, or nil if
+// every entry is pinned.
+func (c *Cache[K, V]) evictionCandidate() *list.Element {
+	victim := c.lru.Back()
+	for c.pinned > 0 && victim != nil && victim.Value.(*entry[K, V]).pinned {
+		victim = victim.Prev()
+	}
+	switch {
+	case victim == nil:
+	case c.priority != nil:
+		victim = c.priorityVictim(victim)
+	case c.weight != nil:
+		victim = c.weightedVictim(victim)
+	}
+	return victim
+}
+
+// markAccessed records the access time of ent if eviction ages are observed.
+func (c *Cache[K, V]) markAccessed(ent *entry[K, V]) {
+	if c.observeAge != nil {
+		ent.accessed = c.now()
+	}
+}
+
+// evictElement removes elem and queues it for the eviction callback.
+func (c *Cache[K, V]) evictElement(elem *list.Element) {
+	ent := c.removeElement(elem)
+	if c.onEvict != nil {
+		c.evicted = append(c.evicted, ent)
+	}
+	c.emit(EventEvict, ent.key, ent.value)
+}
+
+// unlock releases the lock and then invokes the eviction callback for every
+// entry evicted while it was held, followed by the subscribers of the events
+// that happened and the callbacks of the thresholds that were crossed,
+// including those of OnThrash.
+func (c *Cache[K, V]) unlock() {
+	c.applyReads()
+	evicted := c.evicted
+	c.evicted = nil
+	events := c.events
+	c.events = nil
+	subscribers := c.subscribers
+	var calls []thresholdCall
+	if len(c.thresholds) > 0 {
+		calls = c.thresholds.check(c.PortionFilled(), nil)
+	}
+	if len(c.thrashThresholds) > 0 && c.thrash.puts >= thrashBucketPuts {
+		calls = c.thrashThresholds.check(c.thrash.ratio(), calls)
+	}
+	c.mu.Unlock()
+
+	for _, ent := range evicted {
+		c.onEvict(ent.key, ent.value)
+	}
+	deliver(subscribers, events)
+	invokeThresholds(calls)
+}
+
+func (c *Cache[K, V]) removeElement(elem *list.Element) *entry[K, V] {
+	ent := elem.Value.(*entry[K, V])
+	c.unlink(elem)
+	delete(c.items, ent.key)
+	atomic.AddInt64(&c.length, -1)
+	if ent.pinned {
+		c.pinned--
+	}
+	return ent
+}
+
 // Interface compliance
-var _ cache.Cacher[struct{}, struct{}] = (*Cache[struct{}, struct{}])(nil)
+var (
+	_ cache.Cacher[struct{}, struct{}]   = (*Cache[struct{}, struct{}])(nil)
+	_ cache.Iterable[struct{}, struct{}] = (*Cache[struct{}, struct{}])(nil)
+	_ cache.BoundReporter                = (*Cache[struct{}, struct{}])(nil)
+)
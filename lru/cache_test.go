@@ -1,9 +1,19 @@
 package lru
 
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/cache"
 )
 
 func TestContainerCache(t *testing.T) {
@@ -49,3 +59,1243 @@ func TestCacheWithEvictionCallback(t *testing.T) {
 	require.Len(evicted, 1)
 	require.Equal("x", evicted[0])
 }
+
+func TestEntryInfo(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[string, string](3)
+
+	before := time.Now()
+	cache.Put("a", "apple")
+	cache.Put("b", "banana")
+	cache.Put("c", "cherry")
+	after := time.Now()
+
+	_, _ = cache.Get("a")
+	_, _ = cache.Get("a")
+
+	info, ok := cache.EntryInfo("a")
+	require.True(ok)
+	require.Equal(uint64(2), info.Hits)
+	require.Zero(info.Position)
+	require.False(info.Inserted.Before(before))
+	require.False(info.Inserted.After(after))
+
+	info, ok = cache.EntryInfo("b")
+	require.True(ok)
+	require.Zero(info.Hits)
+	require.Equal(2, info.Position)
+
+	// EntryInfo must not promote, so "b" is still the next to be evicted.
+	cache.Put("d", "date")
+	_, ok = cache.EntryInfo("b")
+	require.False(ok)
+
+	info, ok = cache.EntryInfo("c")
+	require.True(ok)
+	require.Equal(2, info.Position)
+}
+
+func TestEvictFunc(t *testing.T) {
+	require := require.New(t)
+
+	evicted := make(map[int]int)
+	cache := NewCacheWithOnEvict[int, int](10, func(k, v int) {
+		evicted[k] = v
+	})
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i*10)
+	}
+
+	removed := cache.EvictFunc(func(k, _ int) bool {
+		return k%2 == 0
+	})
+	require.Equal(5, removed)
+	require.Equal(map[int]int{0: 0, 2: 20, 4: 40, 6: 60, 8: 80}, evicted)
+	require.Equal(5, cache.Len())
+	for i := 0; i < 10; i++ {
+		v, ok := cache.Get(i)
+		require.Equal(i%2 == 1, ok)
+		if ok {
+			require.Equal(i*10, v)
+		}
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[string, string](2)
+	cache.Put("a", "apple")
+	cache.Put("b", "banana")
+	cache.Freeze()
+	require.True(cache.Frozen())
+
+	cache.Put("c", "cherry")
+	cache.Put("a", "avocado")
+	cache.Evict("b")
+	cache.Flush()
+	require.Zero(cache.EvictFunc(func(string, string) bool { return true }))
+
+	require.Equal(2, cache.Len())
+	val, ok := cache.Get("a")
+	require.True(ok)
+	require.Equal("apple", val)
+	_, ok = cache.Get("c")
+	require.False(ok)
+
+	// Recency is still updated by default.
+	info, ok := cache.EntryInfo("a")
+	require.True(ok)
+	require.Zero(info.Position)
+}
+
+func TestFreezeWithOptions(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[string, string](2)
+	cache.Put("a", "apple")
+	cache.Put("b", "banana")
+	cache.FreezeWithOptions(FreezeOptions{
+		PanicOnWrite: true,
+		FreezeOrder:  true,
+	})
+
+	require.Panics(func() { cache.Put("c", "cherry") })
+	require.Panics(func() { cache.Evict("a") })
+	require.Panics(func() { cache.Flush() })
+
+	_, ok := cache.Get("a")
+	require.True(ok)
+	info, ok := cache.EntryInfo("a")
+	require.True(ok)
+	require.Equal(1, info.Position)
+}
+
+func TestStats(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[int, int](2)
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3) // evicts 1
+	cache.Evict(2)  // explicit removals aren't evictions
+
+	_, _ = cache.Get(1)
+	_, _ = cache.Get(2)
+	_, _ = cache.Get(3)
+	_, _ = cache.Get(3)
+
+	require.Equal(CacheStats{
+		Hits:      2,
+		Misses:    2,
+		Evictions: 1,
+		Len:       1,
+		Cap:       2,
+	}, cache.Stats())
+}
+
+func TestCacheStatsStandard(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCache[int, int](4)
+	c.Put(1, 1)
+	_, _ = c.Get(1)
+	_, _ = c.Get(2)
+	require.Equal(cache.Stats{
+		Hits:          1,
+		Misses:        1,
+		Evictions:     0,
+		Len:           1,
+		Cap:           4,
+		PortionFilled: 0.25,
+	}, c.Stats().Standard())
+}
+
+func TestAll(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[int, string](3)
+	cache.Put(1, "one")
+	cache.Put(2, "two")
+	cache.Put(3, "three")
+	_, _ = cache.Get(1)
+
+	var keys []int
+	for k, v := range cache.All() {
+		keys = append(keys, k)
+		// The body may call back into the cache.
+		got, ok := cache.Get(k)
+		require.True(ok)
+		require.Equal(v, got)
+	}
+	require.Equal([]int{1, 3, 2}, keys)
+
+	keys = keys[:0]
+	for k := range cache.All() {
+		keys = append(keys, k)
+		break
+	}
+	require.Len(keys, 1)
+
+	require.Equal(map[int]string{1: "one", 2: "two", 3: "three"}, maps.Collect(cache.All()))
+}
+
+func TestOnEvictReentrant(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		cache *Cache[int, int]
+		lens  []int
+	)
+	cache = NewCacheWithOnEvict[int, int](2, func(k, _ int) {
+		lens = append(lens, cache.Len())
+		_, _ = cache.Get(k)
+	})
+
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3)
+	cache.Evict(2)
+	cache.EvictFunc(func(int, int) bool { return true })
+
+	require.Equal([]int{2, 1, 0}, lens)
+}
+
+func TestPutVersioned(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[string, string](2)
+	require.True(cache.PutVersioned("a", "v2", 2))
+	require.False(cache.PutVersioned("a", "v1", 1))
+	require.False(cache.PutVersioned("a", "v2'", 2))
+
+	val, version, ok := cache.GetVersioned("a")
+	require.True(ok)
+	require.Equal("v2", val)
+	require.Equal(uint64(2), version)
+
+	require.True(cache.PutVersioned("a", "v3", 3))
+	cache.Put("a", "unversioned")
+	_, version, ok = cache.GetVersioned("a")
+	require.True(ok)
+	require.Zero(version)
+}
+
+func TestPutVersionedConcurrent(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		numWriters = 8
+		numWrites  = 1000
+	)
+	cache := NewCache[string, uint64](1)
+
+	var (
+		wg        sync.WaitGroup
+		regressed atomic.Bool
+	)
+	for w := 0; w < numWriters; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var last uint64
+			for i := 0; i < numWrites; i++ {
+				version := uint64(i*numWriters + w + 1)
+				cache.PutVersioned("key", version, version)
+				_, current, ok := cache.GetVersioned("key")
+				if !ok || current < last {
+					regressed.Store(true)
+				}
+				last = current
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.False(regressed.Load())
+	val, version, ok := cache.GetVersioned("key")
+	require.True(ok)
+	require.Equal(uint64(numWrites*numWriters), version)
+	require.Equal(version, val)
+}
+
+func TestFlushInvokesOnEvict(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		cache   *Cache[int, int]
+		evicted []int
+	)
+	cache = NewCacheWithOnEvict[int, int](5, func(k, _ int) {
+		// The cache is already empty when callbacks run.
+		require.Zero(cache.Len())
+		evicted = append(evicted, k)
+	})
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+	}
+	cache.Flush()
+
+	require.Equal([]int{0, 1, 2, 3, 4}, evicted)
+}
+
+func TestNewCacheWithOptions(t *testing.T) {
+	require := require.New(t)
+
+	var evicted []int
+	cache := NewCacheWithOptions(Options[int, int]{
+		Size:            2,
+		OnEvict:         func(k, _ int) { evicted = append(evicted, k) },
+		InitialCapacity: 100,
+	})
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3)
+	require.Equal([]int{1}, evicted)
+	require.Equal(2, cache.Stats().Cap)
+}
+
+func BenchmarkWarmup(b *testing.B) {
+	const size = 100_000
+	for _, hint := range []int{0, size} {
+		b.Run(fmt.Sprintf("hint=%d", hint), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				cache := NewCacheWithOptions(Options[int, int]{
+					Size:            size,
+					InitialCapacity: hint,
+				})
+				for j := 0; j < size; j++ {
+					cache.Put(j, j)
+				}
+			}
+		})
+	}
+}
+
+func TestTouch(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[int, int](2)
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	require.True(cache.Touch(1))
+	require.False(cache.Touch(3))
+
+	cache.Put(3, 3)
+	_, ok := cache.Get(1)
+	require.True(ok)
+	_, ok = cache.Get(2)
+	require.False(ok)
+	// Touch is not counted as a hit or a miss.
+	require.Equal(uint64(1), cache.Stats().Misses)
+	require.Equal(uint64(1), cache.Stats().Hits)
+}
+
+func TestSample(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[int, int](10)
+	require.Empty(cache.Sample(3))
+
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+	}
+	before := slices.Collect(maps.Keys(maps.Collect(cache.All())))
+	order := make([]int, 0, 5)
+	for k := range cache.All() {
+		order = append(order, k)
+	}
+
+	sample := cache.Sample(3)
+	require.Len(sample, 3)
+	for _, k := range sample {
+		require.Contains(before, k)
+	}
+	require.Len(cache.Sample(10), 5)
+	require.Empty(cache.Sample(0))
+
+	after := make([]int, 0, 5)
+	for k := range cache.All() {
+		after = append(after, k)
+	}
+	require.Equal(order, after)
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	require := require.New(t)
+
+	eq := func(a, b int) bool { return a == b }
+	cache := NewCache[string, int](2)
+	require.False(cache.CompareAndSwap("missing", 0, 1, eq))
+
+	cache.Put("a", 1)
+	require.False(cache.CompareAndSwap("a", 2, 3, eq))
+	require.True(cache.CompareAndSwap("a", 1, 2, eq))
+	v, ok := cache.Get("a")
+	require.True(ok)
+	require.Equal(2, v)
+}
+
+func TestCompareAndSwapConcurrent(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		numWorkers   = 8
+		numIncrement = 500
+	)
+	eq := func(a, b int) bool { return a == b }
+	cache := NewCache[string, int](1)
+	cache.Put("counter", 0)
+
+	var (
+		wg        sync.WaitGroup
+		successes atomic.Int64
+	)
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < numIncrement; {
+				current, _ := cache.Get("counter")
+				if cache.CompareAndSwap("counter", current, current+1, eq) {
+					successes.Add(1)
+					i++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	v, ok := cache.Get("counter")
+	require.True(ok)
+	require.Equal(numWorkers*numIncrement, v)
+	require.Equal(int64(numWorkers*numIncrement), successes.Load())
+}
+
+func TestResize(t *testing.T) {
+	require := require.New(t)
+
+	var evicted []int
+	cache := NewCacheWithOnEvict[int, int](4, func(k, _ int) {
+		evicted = append(evicted, k)
+	})
+	for i := 0; i < 4; i++ {
+		cache.Put(i, i)
+	}
+	cache.Resize(2)
+	require.Equal([]int{0, 1}, evicted)
+	require.Equal(2, cache.Len())
+	require.Equal(2, cache.Cap())
+
+	cache.Resize(0)
+	require.Equal(1, cache.Cap())
+}
+
+func TestPutExpireAt(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1_000, 0)
+	var evicted []int
+	cache := NewCacheWithOptions(Options[int, int]{
+		Size:    10,
+		OnEvict: func(k, _ int) { evicted = append(evicted, k) },
+		Clock:   func() time.Time { return now },
+	})
+
+	cache.PutExpireAt(1, 1, now.Add(-time.Second))
+	_, ok := cache.Get(1)
+	require.False(ok)
+	require.Equal([]int{1}, evicted)
+	require.Zero(cache.Len())
+
+	cache.PutExpireAt(2, 2, now.Add(time.Minute))
+	v, ok := cache.Get(2)
+	require.True(ok)
+	require.Equal(2, v)
+
+	// Expiry is inclusive of t.
+	now = now.Add(time.Minute)
+	require.False(cache.Contains(2))
+
+	// Put clears a previously set expiry.
+	cache.PutWithTTL(3, 3, time.Second)
+	cache.Put(3, 33)
+	now = now.Add(time.Hour)
+	v, ok = cache.Get(3)
+	require.True(ok)
+	require.Equal(33, v)
+}
+
+func TestPutEvicting(t *testing.T) {
+	require := require.New(t)
+
+	var callbacks []int
+	cache := NewCacheWithOnEvict[int, int](2, func(k, _ int) {
+		callbacks = append(callbacks, k)
+	})
+
+	_, evicted := cache.PutEvicting(1, 1)
+	require.False(evicted)
+	_, evicted = cache.PutEvicting(2, 2)
+	require.False(evicted)
+
+	// Updating an existing key at capacity doesn't evict.
+	_, evicted = cache.PutEvicting(1, 11)
+	require.False(evicted)
+
+	key, evicted := cache.PutEvicting(3, 3)
+	require.True(evicted)
+	require.Equal(2, key)
+	require.Equal([]int{2}, callbacks)
+	require.Equal(uint64(1), cache.Stats().Evictions)
+}
+
+func TestEvictionAgeObserver(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1_000, 0)
+	var ages []time.Duration
+	cache := NewCacheWithOptions(Options[int, int]{
+		Size:          2,
+		OnEvictionAge: func(age time.Duration) { ages = append(ages, age) },
+		Clock:         func() time.Time { return now },
+	})
+
+	cache.Put(1, 1)
+	now = now.Add(time.Second)
+	cache.Put(2, 2)
+	now = now.Add(4 * time.Second)
+	cache.Put(3, 3) // evicts 1, last accessed 5s ago
+	_, _ = cache.Get(2)
+	now = now.Add(2 * time.Second)
+	cache.Put(4, 4) // evicts 3, last accessed 2s ago
+
+	// Explicit removals are not observed.
+	cache.Delete(2)
+	require.Equal([]time.Duration{5 * time.Second, 2 * time.Second}, ages)
+
+	// An observer set later is called too, and clearing it leaves
+	// OnEvictionAge active.
+	var observed int
+	cache.SetEvictionAgeObserver(func(time.Duration) { observed++ })
+	cache.Put(5, 5)
+	cache.Put(6, 6)
+	require.Len(ages, 3)
+	require.Equal(1, observed)
+	cache.SetEvictionAgeObserver(nil)
+	cache.Put(7, 7)
+	require.Len(ages, 4)
+	require.Equal(1, observed)
+}
+
+func TestEvictMany(t *testing.T) {
+	require := require.New(t)
+
+	var evicted []int
+	cache := NewCacheWithOnEvict[int, int](10, func(k, _ int) {
+		evicted = append(evicted, k)
+	})
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+	}
+
+	require.Equal(3, cache.EvictMany([]int{4, 0, 2, 2, 9}))
+	require.Equal([]int{4, 0, 2}, evicted)
+	require.Equal(2, cache.Len())
+	require.True(cache.Contains(1))
+	require.True(cache.Contains(3))
+	require.Zero(cache.EvictMany(nil))
+}
+
+func BenchmarkEvictMany(b *testing.B) {
+	const size = 1_000
+	keys := make([]int, size)
+	for i := range keys {
+		keys[i] = i
+	}
+	fill := func(cache *Cache[int, int]) {
+		for _, k := range keys {
+			cache.Put(k, k)
+		}
+	}
+
+	b.Run("loop", func(b *testing.B) {
+		cache := NewCache[int, int](size)
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			fill(cache)
+			b.StartTimer()
+			for _, k := range keys {
+				cache.Evict(k)
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		cache := NewCache[int, int](size)
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			fill(cache)
+			b.StartTimer()
+			cache.EvictMany(keys)
+		}
+	})
+}
+
+func TestPutOrdered(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[int, string](3)
+	keys := []int{5, 4, 3, 2, 1}
+	values := []string{"a", "b", "c", "d", "e"}
+	require.NoError(cache.PutOrdered(keys, values))
+
+	var (
+		gotKeys   []int
+		gotValues []string
+	)
+	for k, v := range cache.All() {
+		gotKeys = append(gotKeys, k)
+		gotValues = append(gotValues, v)
+	}
+	require.Equal([]int{1, 2, 3}, gotKeys)
+	require.Equal([]string{"e", "d", "c"}, gotValues)
+
+	err := cache.PutOrdered([]int{1}, nil)
+	require.ErrorIs(err, ErrLengthMismatch)
+	v, ok := cache.Get(1)
+	require.True(ok)
+	require.Equal("e", v)
+}
+
+func TestGetOrDefault(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[int, string](2)
+	cache.Put(1, "one")
+	cache.Put(2, "two")
+	require.Equal("one", cache.GetOrDefault(1, "default"))
+	require.Equal("default", cache.GetOrDefault(3, "default"))
+	require.Equal("two", cache.GetOrZero(2))
+	require.Empty(cache.GetOrZero(3))
+
+	stats := cache.Stats()
+	require.Equal(uint64(2), stats.Hits)
+	require.Equal(uint64(2), stats.Misses)
+}
+
+func TestGetOrdered(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[int, string](3)
+	cache.Put(1, "one")
+	cache.Put(2, "two")
+	cache.Put(3, "three")
+
+	values, found := cache.GetOrdered([]int{3, 4, 1, 5, 3})
+	require.Equal([]string{"three", "", "one", "", "three"}, values)
+	require.Equal([]bool{true, false, true, false, true}, found)
+
+	// Hits are promoted, so 2 is now the least recently used entry.
+	cache.Put(6, "six")
+	require.False(cache.Contains(2))
+
+	stats := cache.Stats()
+	require.Equal(uint64(3), stats.Hits)
+	require.Equal(uint64(2), stats.Misses)
+
+	values, found = cache.GetOrdered(nil)
+	require.Empty(values)
+	require.Empty(found)
+}
+
+func TestDrain(t *testing.T) {
+	require := require.New(t)
+
+	var evicted []int
+	cache := NewCacheWithOnEvict[int, int](3, func(k, _ int) {
+		evicted = append(evicted, k)
+	})
+	for i := 0; i < 3; i++ {
+		cache.Put(i, i*10)
+	}
+	_, _ = cache.Get(0)
+
+	entries := cache.Drain()
+	require.Equal([]Entry[int, int]{
+		{Key: 0, Value: 0},
+		{Key: 2, Value: 20},
+		{Key: 1, Value: 10},
+	}, entries)
+	require.Zero(cache.Len())
+	require.Equal([]int{1, 2, 0}, evicted)
+	require.Empty(cache.Drain())
+
+	// Reinserting from least to most recently used restores the order.
+	for _, entry := range slices.Backward(entries) {
+		cache.Put(entry.Key, entry.Value)
+	}
+	require.Equal(entries, cache.Drain())
+}
+
+func BenchmarkGetWithPortionFilledPoller(b *testing.B) {
+	const size = 1024
+	for _, poll := range []bool{false, true} {
+		b.Run(fmt.Sprintf("poll=%t", poll), func(b *testing.B) {
+			cache := NewCache[int, int](size)
+			for i := 0; i < size; i++ {
+				cache.Put(i, i)
+			}
+
+			done := make(chan struct{})
+			var wg sync.WaitGroup
+			if poll {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for {
+						select {
+						case <-done:
+							return
+						default:
+							_ = cache.PortionFilled()
+							_ = cache.Len()
+						}
+					}
+				}()
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					_, _ = cache.Get(i % size)
+					i++
+				}
+			})
+			b.StopTimer()
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+func TestPin(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[int, int](3)
+	cache.Put(-1, -1)
+	require.True(cache.Pin(-1))
+	require.False(cache.Pin(-2))
+
+	for i := 0; i < 100; i++ {
+		cache.Put(i, i)
+	}
+	v, ok := cache.Get(-1)
+	require.True(ok)
+	require.Equal(-1, v)
+	require.Equal(3, cache.Len())
+
+	// Once every entry is pinned, new keys are rejected.
+	require.True(cache.Pin(98))
+	require.True(cache.Pin(99))
+	_, evicted := cache.PutEvicting(100, 100)
+	require.False(evicted)
+	require.False(cache.Contains(100))
+	require.Equal(3, cache.Len())
+
+	// Updates of pinned keys still succeed.
+	cache.Put(99, 199)
+	require.Equal(199, cache.GetOrZero(99))
+
+	require.True(cache.Unpin(98))
+	key, evicted := cache.PutEvicting(100, 100)
+	require.True(evicted)
+	require.Equal(98, key)
+
+	// Explicit removals ignore pins.
+	cache.Delete(-1)
+	require.False(cache.Contains(-1))
+	cache.Resize(1)
+	require.Equal(1, cache.Len())
+	require.True(cache.Contains(99))
+}
+
+// heapInUse returns the bytes of heap in use after a full collection.
+func heapInUse() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
+
+func TestCompact(t *testing.T) {
+	require := require.New(t)
+
+	const n = 200_000
+	cache := NewCache[int, int](n)
+	for i := range n {
+		cache.Put(i, i)
+	}
+	cache.EvictFunc(func(k, _ int) bool { return k >= 10 })
+	require.Equal(10, cache.Len())
+
+	before := heapInUse()
+	cache.Compact()
+	after := heapInUse()
+	// Best effort: the map of n entries accounts for several megabytes, so
+	// releasing it dwarfs any other allocation in the test binary.
+	require.Less(after, before)
+
+	require.Equal(10, cache.Len())
+	for i := range 10 {
+		v, ok := cache.Get(i)
+		require.True(ok)
+		require.Equal(i, v)
+	}
+	cache.Put(10, 10)
+	require.Equal(11, cache.Len())
+}
+
+func TestCompactLFU(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCacheWithPolicy[int, int](3, PolicyLFU)
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3)
+	_, _ = cache.Get(1)
+	cache.Evict(3)
+	cache.Compact()
+
+	cache.Put(4, 4)
+	cache.Put(5, 5) // evicts the least frequently used, 2
+	require.True(cache.Contains(1))
+	require.False(cache.Contains(2))
+}
+
+func TestOnEvictVeto(t *testing.T) {
+	require := require.New(t)
+
+	var evicted []int
+	cache := NewCacheWithOptions(Options[int, int]{
+		Size:    3,
+		OnEvict: func(k, _ int) { evicted = append(evicted, k) },
+		// Key 1 is about to be needed, so it vetoes its eviction once.
+		OnEvictVeto: func(k, v int) bool { return k == 1 && v == 1 },
+	})
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3)
+
+	cache.Put(4, 4)
+	require.True(cache.Contains(1))
+	require.False(cache.Contains(2))
+	require.Equal([]int{2}, evicted)
+
+	// 1 was promoted, so 3 is now the least recently used entry.
+	cache.Put(5, 5)
+	require.False(cache.Contains(3))
+	require.Equal(uint64(2), cache.Stats().Evictions)
+}
+
+func TestOnEvictVetoBounded(t *testing.T) {
+	for _, policy := range []Policy{PolicyLRU, PolicyLFU, PolicyFIFO} {
+		t.Run(fmt.Sprint(policy), func(t *testing.T) {
+			require := require.New(t)
+
+			vetoes := 0
+			cache := NewCacheWithOptions(Options[int, int]{
+				Size:   3,
+				Policy: policy,
+				OnEvictVeto: func(int, int) bool {
+					vetoes++
+					return true
+				},
+			})
+			cache.Put(1, 1)
+			cache.Put(2, 2)
+			cache.Put(3, 3)
+
+			// Every entry vetoes once, then the next candidate is evicted.
+			cache.Put(4, 4)
+			require.Equal(3, vetoes)
+			require.Equal(3, cache.Len())
+			require.True(cache.Contains(4))
+			require.Equal(uint64(1), cache.Stats().Evictions)
+		})
+	}
+}
+
+func TestDelGet(t *testing.T) {
+	require := require.New(t)
+
+	var evicted []int
+	cache := NewCacheWithOnEvict[int, int](2, func(k, _ int) {
+		evicted = append(evicted, k)
+	})
+	cache.Put(1, 10)
+
+	v, ok := cache.DelGet(1)
+	require.True(ok)
+	require.Equal(10, v)
+	require.False(cache.Contains(1))
+	require.Equal([]int{1}, evicted)
+
+	_, ok = cache.DelGet(1)
+	require.False(ok)
+}
+
+func TestDelGetConcurrent(t *testing.T) {
+	require := require.New(t)
+
+	const callers = 16
+	for range 100 {
+		cache := NewCache[int, int](2)
+		cache.Put(1, 1)
+
+		var (
+			wg  sync.WaitGroup
+			got atomic.Int64
+		)
+		for range callers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, ok := cache.DelGet(1); ok {
+					got.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		require.Equal(int64(1), got.Load())
+	}
+}
+
+func TestBounded(t *testing.T) {
+	budget := NewSharedBudget[int, int](10, nil)
+	for name, c := range map[string]cache.Cacher[int, int]{
+		"cache":     NewCache[int, int](2),
+		"sized":     NewSizedCache[int, int](2, nil),
+		"ring":      NewRingCache[int, int](2, PolicyLRU),
+		"namespace": budget.Namespace("a"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.True(t, cache.IsBounded(c))
+		})
+	}
+}
+
+func TestHotKeys(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(NewCache[int, int](2).HotKeys(1))
+
+	cache := NewCacheWithOptions(Options[int, int]{
+		Size:             16,
+		HotKeySampleRate: 3,
+	})
+	for i := range 10000 {
+		if i%4 == 0 {
+			cache.Put(i, i)
+		} else {
+			_, _ = cache.Get(42)
+		}
+	}
+	top := cache.HotKeys(1)
+	require.Len(top, 1)
+	require.Equal(42, top[0].Key)
+	require.InDelta(7500, float64(top[0].Count), 500)
+}
+
+func TestCacheWithClone(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCacheWithClone[string](2, slices.Clone[[]int])
+
+	stored := []int{1, 2, 3}
+	c.Put("a", stored)
+	stored[0] = 100
+
+	got, ok := c.Get("a")
+	require.True(ok)
+	require.Equal([]int{1, 2, 3}, got)
+
+	got[1] = 200
+	for _, v := range c.All() {
+		v[2] = 300
+	}
+	computed, err := c.GetOrCompute("a", func() ([]int, error) {
+		return nil, errors.New("unexpected compute")
+	})
+	require.NoError(err)
+	computed[0] = 400
+
+	require.Equal([]int{1, 2, 3}, c.GetOrZero("a"))
+}
+
+func TestFlushColdest(t *testing.T) {
+	tests := []struct {
+		name     string
+		fraction float64
+		want     []int
+	}{
+		{name: "negative", fraction: -1, want: []int{}},
+		{name: "zero", fraction: 0, want: []int{}},
+		{name: "cold half", fraction: 0.5, want: []int{1, 2, 3, 5, 0}},
+		{name: "rounds down", fraction: 0.35, want: []int{1, 2, 3}},
+		{name: "all", fraction: 1, want: []int{1, 2, 3, 5, 0, 4, 6, 7, 8, 9}},
+		{name: "above one", fraction: 2, want: []int{1, 2, 3, 5, 0, 4, 6, 7, 8, 9}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			evicted := []int{}
+			c := NewCacheWithOnEvict(10, func(k, _ int) {
+				evicted = append(evicted, k)
+			})
+			for i := range 10 {
+				c.Put(i, i)
+			}
+			// Recency from least to most recent: 1 2 3 5 0 4 6 7 8 9.
+			c.Get(0)
+			c.Get(4)
+			for i := 6; i < 10; i++ {
+				c.Get(i)
+			}
+
+			removed := c.FlushColdest(tt.fraction)
+			require.Equal(len(tt.want), removed)
+			require.Equal(tt.want, evicted)
+			require.Equal(10-removed, c.Len())
+			for _, k := range tt.want {
+				require.False(c.Contains(k))
+			}
+		})
+	}
+}
+
+func TestCacheOrDisabled(t *testing.T) {
+	require := require.New(t)
+
+	var evicted []int
+	c := NewCacheWithOptions(Options[int, int]{
+		AllowZeroSize: true,
+		OnEvict: func(k, _ int) {
+			evicted = append(evicted, k)
+		},
+	})
+	c.Put(1, 1)
+	c.PutWithTTL(2, 2, time.Minute)
+	require.False(c.PutVersioned(3, 3, 1))
+	_, evictedAny := c.PutEvicting(4, 4)
+	require.False(evictedAny)
+	v, err := c.GetOrCompute(5, func() (int, error) { return 5, nil })
+	require.NoError(err)
+	require.Equal(5, v)
+
+	for k := range 6 {
+		_, ok := c.Get(k)
+		require.False(ok)
+	}
+	require.Zero(c.Len())
+	require.Zero(c.Cap())
+	require.Zero(c.PortionFilled())
+	require.Empty(evicted)
+
+	c.Resize(1)
+	c.Put(1, 1)
+	require.True(c.Contains(1))
+	c.Resize(0)
+	require.Zero(c.Len())
+	require.Equal([]int{1}, evicted)
+
+	require.Equal(1, NewCache[int, int](0).Cap())
+	require.Zero(NewCacheOrDisabled[int, int](0).Cap())
+}
+
+func TestOldestNewest(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCache[int, int](3)
+	_, _, ok := c.Oldest()
+	require.False(ok)
+	_, _, ok = c.Newest()
+	require.False(ok)
+
+	assertEnds := func(oldest, newest int) {
+		t.Helper()
+		k, v, ok := c.Oldest()
+		require.True(ok)
+		require.Equal(oldest, k)
+		require.Equal(oldest*10, v)
+		k, v, ok = c.Newest()
+		require.True(ok)
+		require.Equal(newest, k)
+		require.Equal(newest*10, v)
+	}
+
+	c.Put(1, 10)
+	assertEnds(1, 1)
+	c.Put(2, 20)
+	c.Put(3, 30)
+	assertEnds(1, 3)
+
+	// Peeking at the ends doesn't promote them.
+	assertEnds(1, 3)
+	c.Get(1)
+	assertEnds(2, 1)
+	c.Put(4, 40)
+	assertEnds(3, 4)
+	c.Delete(3)
+	assertEnds(1, 4)
+	require.True(c.Pin(1))
+	assertEnds(4, 4)
+
+	c.Flush()
+	_, _, ok = c.Oldest()
+	require.False(ok)
+}
+
+func TestEvictOldest(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCache[int, int](5)
+	for i := range 5 {
+		c.Put(i, i)
+	}
+	c.Get(0)
+
+	require.Equal(2, c.EvictOldest(2))
+	var keys []int
+	for k := range c.All() {
+		keys = append(keys, k)
+	}
+	require.Equal([]int{0, 4, 3}, keys)
+	require.Equal(3, c.EvictOldest(10))
+	require.Zero(c.Len())
+	require.Equal(uint64(5), c.Stats().Evictions)
+}
+
+func TestMerge(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCache[string, int](2)
+	sum := func(old, new int) int { return old + new }
+	c.Merge("a", 1, sum)
+	c.Merge("a", 2, sum)
+	c.Put("b", 5)
+	c.Merge("b", 1, sum)
+	require.Equal(3, c.GetOrZero("a"))
+	require.Equal(6, c.GetOrZero("b"))
+
+	// Merging promotes the entry, so "b" is evicted next.
+	c.Merge("a", 0, sum)
+	c.Put("c", 7)
+	require.False(c.Contains("b"))
+	require.Equal(3, c.GetOrZero("a"))
+}
+
+func TestMergeConcurrent(t *testing.T) {
+	const (
+		goroutines = 16
+		merges     = 1000
+	)
+
+	c := NewCache[string, int](1)
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range merges {
+				c.Merge("sum", 1, func(old, new int) int { return old + new })
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, goroutines*merges, c.GetOrZero("sum"))
+}
+
+func TestNoPromoteOnWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options[int, int]
+		evicted  int
+		retained int
+	}{
+		{
+			name:     "default promotes",
+			opts:     Options[int, int]{Size: 2},
+			evicted:  2,
+			retained: 1,
+		},
+		{
+			name:     "writes don't promote",
+			opts:     Options[int, int]{Size: 2, NoPromoteOnWrite: true},
+			evicted:  1,
+			retained: 2,
+		},
+		{
+			name:     "writes don't count under LFU",
+			opts:     Options[int, int]{Size: 2, Policy: PolicyLFU, NoPromoteOnWrite: true},
+			evicted:  1,
+			retained: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			c := NewCacheWithOptions(tt.opts)
+			c.Put(1, 1)
+			c.Put(2, 2)
+			c.Put(1, 10)
+			c.Merge(1, 1, func(old, new int) int { return old + new })
+			c.Put(3, 3)
+
+			require.False(c.Contains(tt.evicted))
+			require.True(c.Contains(tt.retained))
+			require.True(c.Contains(3))
+		})
+	}
+}
+
+func TestNoPromoteOnWriteUpdatesValue(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCacheWithOptions(Options[int, int]{Size: 2, NoPromoteOnWrite: true})
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Put(1, 10)
+	v, ok := c.Get(1)
+	require.True(ok)
+	require.Equal(10, v)
+
+	// Reads still promote.
+	c.Put(3, 3)
+	require.True(c.Contains(1))
+	require.False(c.Contains(2))
+}
+
+func TestPutReporting(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCache[int, int](2)
+	require.True(c.PutReporting(1, 1))
+	require.False(c.PutReporting(1, 10))
+	v, ok := c.Get(1)
+	require.True(ok)
+	require.Equal(10, v)
+
+	// Evicting a key makes its next put an insert again.
+	require.True(c.PutReporting(2, 2))
+	require.True(c.PutReporting(3, 3))
+	require.True(c.PutReporting(1, 1))
+
+	c.Freeze()
+	require.False(c.PutReporting(4, 4))
+	require.False(c.Contains(4))
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrComputePanicked is returned to GetOrCompute callers that waited on a
+// computation that panicked, and to LoadingCache callers whose loader panicked,
+// wrapped with the panic value and the loader's stack.
+var ErrComputePanicked = errors.New("lru: compute panicked")
+
+// call is a computation in flight. value and err are written before wg is
+// released.
+type call[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+// GetOrCompute returns the cached value of key, calling compute to produce and
+// store it on a miss. Concurrent calls for the same missing key are coalesced
+// onto a single computation, and all of them receive its result. Errors are
+// returned to every waiting caller and are not cached. compute runs without
+// the lock held and may call back into the cache.
+//
+// Every call that does not find the key counts as a miss. Stats additionally
+// reports the number of computations started, coalesced and failed.
+func (c *Cache[K, V]) GetOrCompute(key K, compute func() (V, error)) (V, error) {
+	return c.getOrCompute(key, 0, compute)
+}
+
+// GetOrComputeWithTTL is like GetOrCompute, but stores the computed value for
+// the duration ttl, as PutWithTTL does, so that the key is computed again once
+// the value has expired. The ttl is measured from when the computation
+// completes rather than from when it started, so a slow computation doesn't
+// store a value that is already expired, or about to be. A ttl <= 0 stores
+// the value without expiry.
+//
+// Calls for the same key are coalesced with those of GetOrCompute, and the
+// value is stored as configured by the call that started the computation.
+func (c *Cache[K, V]) GetOrComputeWithTTL(key K, ttl time.Duration, compute func() (V, error)) (V, error) {
+	return c.getOrCompute(key, ttl, compute)
+}
+
+// getOrCompute implements GetOrCompute, storing the value for the duration
+// ttl if positive.
+func (c *Cache[K, V]) getOrCompute(key K, ttl time.Duration, compute func() (V, error)) (value V, err error) {
+	c.mu.Lock()
+	if value, ok := c.get(key); ok {
+		c.unlock()
+		atomic.AddUint64(&c.hits, 1)
+		return c.cloned(value), nil
+	}
+	atomic.AddUint64(&c.misses, 1)
+
+	if cl, ok := c.calls[key]; ok {
+		c.unlock()
+		atomic.AddUint64(&c.coalesced, 1)
+		cl.wg.Wait()
+		if cl.err != nil {
+			return cl.value, cl.err
+		}
+		return c.cloned(cl.value), nil
+	}
+
+	cl := &call[V]{err: ErrComputePanicked}
+	cl.wg.Add(1)
+	if c.calls == nil {
+		c.calls = make(map[K]*call[V])
+	}
+	c.calls[key] = cl
+	c.unlock()
+	atomic.AddUint64(&c.computes, 1)
+
+	defer func() {
+		// cl.err is still ErrComputePanicked if compute panicked, so a
+		// panic counts as an error too.
+		if cl.err != nil {
+			atomic.AddUint64(&c.computeErrors, 1)
+		}
+		c.mu.Lock()
+		delete(c.calls, key)
+		if cl.err == nil && !c.frozen {
+			c.put(key, cl.value)
+			if elem, ok := c.items[key]; ok && ttl > 0 {
+				elem.Value.(*entry[K, V]).expiry = c.now().Add(ttl)
+			}
+		}
+		c.unlock()
+		if cl.err == nil {
+			// The waiters clone cl.value once released, so the caller's
+			// copy must be taken first.
+			value = c.cloned(cl.value)
+		}
+		cl.wg.Done()
+	}()
+
+	cl.value, cl.err = compute()
+	return cl.value, cl.err
+}
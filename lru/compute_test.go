@@ -0,0 +1,298 @@
+package lru
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOrCompute(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[int, int](2)
+	v, err := cache.GetOrCompute(1, func() (int, error) { return 10, nil })
+	require.NoError(err)
+	require.Equal(10, v)
+
+	// The computed value is cached.
+	v, err = cache.GetOrCompute(1, func() (int, error) {
+		require.FailNow("unexpected compute")
+		return 0, nil
+	})
+	require.NoError(err)
+	require.Equal(10, v)
+
+	errTest := errors.New("test")
+	_, err = cache.GetOrCompute(2, func() (int, error) { return 0, errTest })
+	require.ErrorIs(err, errTest)
+	require.False(cache.Contains(2))
+
+	// A panicking computation counts as failed too.
+	require.Panics(func() {
+		_, _ = cache.GetOrCompute(3, func() (int, error) { panic("test") })
+	})
+	require.False(cache.Contains(3))
+
+	stats := cache.Stats()
+	require.Equal(uint64(1), stats.Hits)
+	require.Equal(uint64(3), stats.Misses)
+	require.Equal(uint64(3), stats.Computes)
+	require.Zero(stats.Coalesced)
+	require.Equal(uint64(2), stats.ComputeErrors)
+}
+
+func TestGetOrComputeCoalesces(t *testing.T) {
+	require := require.New(t)
+
+	const callers = 16
+	var (
+		cache   = NewCache[int, int](10)
+		release = make(chan struct{})
+		started = make(chan struct{})
+		wg      sync.WaitGroup
+		results = make([]int, callers)
+	)
+
+	// Start one computation and block it until every other caller has
+	// coalesced onto it.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = cache.GetOrCompute(1, func() (int, error) {
+			close(started)
+			<-release
+			return 42, nil
+		})
+	}()
+	<-started
+
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = cache.GetOrCompute(1, func() (int, error) {
+				return 0, errors.New("unexpected compute")
+			})
+		}()
+	}
+	require.Eventually(func() bool {
+		return cache.Stats().Coalesced == callers-1
+	}, time.Second*5, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for _, result := range results {
+		require.Equal(42, result)
+	}
+	stats := cache.Stats()
+	require.Equal(uint64(1), stats.Computes)
+	require.Equal(uint64(callers-1), stats.Coalesced)
+}
+
+func TestGetOrComputeCoalescedClones(t *testing.T) {
+	require := require.New(t)
+
+	const callers = 8
+	var (
+		cache   = NewCacheWithClone[int](10, slices.Clone[[]int])
+		release = make(chan struct{})
+		started = make(chan struct{})
+		wg      sync.WaitGroup
+		results = make([][]int, callers)
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		value, _ := cache.GetOrCompute(1, func() ([]int, error) {
+			close(started)
+			<-release
+			return []int{1, 2, 3}, nil
+		})
+		// Mutating the result must not race with the waiters cloning theirs.
+		for i := range value {
+			value[i] = 0
+		}
+		results[0] = value
+	}()
+	<-started
+
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = cache.GetOrCompute(1, func() ([]int, error) {
+				return nil, errors.New("unexpected compute")
+			})
+		}()
+	}
+	require.Eventually(func() bool {
+		return cache.Stats().Coalesced == callers-1
+	}, time.Second*5, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal([]int{0, 0, 0}, results[0])
+	for _, result := range results[1:] {
+		require.Equal([]int{1, 2, 3}, result)
+	}
+	v, ok := cache.Get(1)
+	require.True(ok)
+	require.Equal([]int{1, 2, 3}, v)
+}
+
+func TestGetOrComputePanic(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[int, int](10)
+	require.Panics(func() {
+		_, _ = cache.GetOrCompute(1, func() (int, error) { panic("test") })
+	})
+
+	// A panicking computation doesn't leave the key stuck in flight.
+	v, err := cache.GetOrCompute(1, func() (int, error) { return 1, nil })
+	require.NoError(err)
+	require.Equal(1, v)
+}
+
+// fakeClock is a clock for tests that only moves when advanced.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestGetOrComputeWithTTL(t *testing.T) {
+	require := require.New(t)
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cache := NewCacheWithOptions(Options[int, int]{Size: 2, Clock: clock.Now})
+	computes := 0
+	compute := func() (int, error) {
+		computes++
+		return 10 * computes, nil
+	}
+
+	v, err := cache.GetOrComputeWithTTL(1, time.Minute, compute)
+	require.NoError(err)
+	require.Equal(10, v)
+
+	clock.Advance(time.Minute - 1)
+	v, err = cache.GetOrComputeWithTTL(1, time.Minute, compute)
+	require.NoError(err)
+	require.Equal(10, v)
+
+	// The expired value is computed again, with a new expiry.
+	clock.Advance(1)
+	v, err = cache.GetOrComputeWithTTL(1, time.Minute, compute)
+	require.NoError(err)
+	require.Equal(20, v)
+	clock.Advance(time.Minute - 1)
+	require.True(cache.Contains(1))
+	clock.Advance(1)
+	require.False(cache.Contains(1))
+	require.Equal(2, computes)
+
+	// A ttl <= 0 stores the value without expiry.
+	_, err = cache.GetOrComputeWithTTL(2, 0, compute)
+	require.NoError(err)
+	clock.Advance(time.Hour)
+	require.True(cache.Contains(2))
+}
+
+func TestGetOrComputeWithTTLFromCompletion(t *testing.T) {
+	require := require.New(t)
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cache := NewCacheWithOptions(Options[int, int]{Size: 2, Clock: clock.Now})
+
+	// The computation takes longer than the ttl.
+	_, err := cache.GetOrComputeWithTTL(1, time.Second, func() (int, error) {
+		clock.Advance(2 * time.Second)
+		return 1, nil
+	})
+	require.NoError(err)
+	clock.Advance(time.Second - 1)
+	v, ok := cache.Get(1)
+	require.True(ok)
+	require.Equal(1, v)
+}
+
+func TestGetOrComputeWithTTLErrorsNotCached(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[int, int](2)
+	errTest := errors.New("test")
+	_, err := cache.GetOrComputeWithTTL(1, time.Minute, func() (int, error) { return 0, errTest })
+	require.ErrorIs(err, errTest)
+	require.False(cache.Contains(1))
+
+	v, err := cache.GetOrComputeWithTTL(1, time.Minute, func() (int, error) { return 1, nil })
+	require.NoError(err)
+	require.Equal(1, v)
+	require.Equal(uint64(2), cache.Stats().Computes)
+	require.Equal(uint64(1), cache.Stats().ComputeErrors)
+}
+
+func TestGetOrComputeWithTTLCoalesces(t *testing.T) {
+	require := require.New(t)
+
+	const callers = 16
+	var (
+		clock    = &fakeClock{now: time.Unix(0, 0)}
+		cache    = NewCacheWithOptions(Options[int, int]{Size: 2, Clock: clock.Now})
+		computes atomic.Int64
+		release  = make(chan struct{})
+		wg       sync.WaitGroup
+		results  = make([]int, callers)
+	)
+	compute := func() (int, error) {
+		computes.Add(1)
+		<-release
+		return 7, nil
+	}
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = cache.GetOrComputeWithTTL(1, time.Minute, compute)
+		}()
+	}
+	// Wait for every caller to either start or coalesce onto the computation.
+	require.Eventually(func() bool {
+		s := cache.Stats()
+		return s.Computes+s.Coalesced == callers
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(int64(1), computes.Load())
+	for _, result := range results {
+		require.Equal(7, result)
+	}
+
+	// Once expired, the value is computed once more.
+	clock.Advance(time.Minute)
+	release = make(chan struct{})
+	close(release)
+	v, err := cache.GetOrComputeWithTTL(1, time.Minute, compute)
+	require.NoError(err)
+	require.Equal(7, v)
+	require.Equal(int64(2), computes.Load())
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import "context"
+
+// RequestCacheSize is the capacity of the caches created by FromContext.
+const RequestCacheSize = 128
+
+// contextKey identifies the request cache of a key and value type in a
+// context. Each instantiation is a distinct key.
+type contextKey[K comparable, V any] struct{}
+
+// FromContext returns the request-scoped cache of K to V values attached to
+// ctx, creating one of RequestCacheSize entries if there is none. The returned
+// context carries the cache and is ctx itself if the cache already existed;
+// pass it to downstream calls so that they share memoized results.
+//
+// The cache lives as long as the context that carries it, and is flushed once
+// ctx is done so that its values are released even if the context is still
+// referenced. Caches are keyed by type only, so unrelated callers memoizing
+// the same K and V types in one request share a cache; use a distinct key type
+// to keep them apart.
+func FromContext[K comparable, V any](ctx context.Context) (*Cache[K, V], context.Context) {
+	if c, ok := ctx.Value(contextKey[K, V]{}).(*Cache[K, V]); ok {
+		return c, ctx
+	}
+	c := NewCache[K, V](RequestCacheSize)
+	context.AfterFunc(ctx, c.Flush)
+	return c, context.WithValue(ctx, contextKey[K, V]{}, c)
+}
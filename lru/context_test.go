@@ -0,0 +1,46 @@
+package lru
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext(t *testing.T) {
+	require := require.New(t)
+
+	cache, ctx := FromContext[string, int](context.Background())
+	cache.Put("block", 1)
+
+	// A downstream call in the same request sees the memoized value.
+	shared, sharedCtx := FromContext[string, int](ctx)
+	require.Same(cache, shared)
+	require.Equal(ctx, sharedCtx)
+	v, ok := shared.Get("block")
+	require.True(ok)
+	require.Equal(1, v)
+
+	// Another request does not.
+	other, _ := FromContext[string, int](context.Background())
+	require.NotSame(cache, other)
+	require.False(other.Contains("block"))
+
+	// Nor does a cache of different types in the same request.
+	typed, _ := FromContext[string, string](ctx)
+	require.False(typed.Contains("block"))
+}
+
+func TestFromContextFlushedWhenDone(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cache, _ := FromContext[int, int](ctx)
+	cache.Put(1, 1)
+
+	cancel()
+	require.Eventually(func() bool {
+		return cache.Len() == 0
+	}, time.Second, time.Millisecond)
+}
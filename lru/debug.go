@@ -0,0 +1,84 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+)
+
+// DumpLimit is the number of keys Dump lists before eliding the rest.
+const DumpLimit = 100
+
+var (
+	_ fmt.Stringer = (*Cache[struct{}, struct{}])(nil)
+	_ fmt.Stringer = (*SizedCache[struct{}, struct{}])(nil)
+)
+
+// String summarizes the cache's length, capacity, fill and counters on one
+// line, for logs. It doesn't acquire the lock.
+func (c *Cache[K, V]) String() string {
+	s := c.Stats()
+	return fmt.Sprintf(
+		"lru.Cache{len=%d cap=%d fill=%.1f%% hits=%d misses=%d evictions=%d}",
+		s.Len, s.Cap, 100*c.PortionFilled(), s.Hits, s.Misses, s.Evictions,
+	)
+}
+
+// Dump returns String followed by the keys from most to least recently used,
+// one per line, listing at most DumpLimit of them. It holds the lock while
+// walking the keys and is meant for debugging, not hot paths.
+func (c *Cache[K, V]) Dump() string {
+	summary := c.String()
+	c.mu.Lock()
+	defer c.unlock()
+	c.applyReads()
+	return dumpKeys[K, *entry[K, V]](summary, c.lru, func(ent *entry[K, V]) K {
+		return ent.key
+	})
+}
+
+// String summarizes the cache's length, size, capacity and fill on one line,
+// for logs. SizedCache keeps no hit or miss counters, so none are reported.
+func (c *SizedCache[K, V]) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fill := 0.0
+	if c.maxSize > 0 {
+		fill = 100 * float64(c.currentSize) / float64(c.maxSize)
+	}
+	return fmt.Sprintf(
+		"lru.SizedCache{len=%d size=%d cap=%d fill=%.1f%%}",
+		len(c.items), c.currentSize, c.maxSize, fill,
+	)
+}
+
+// Dump returns String followed by the keys from most to least recently used,
+// one per line, listing at most DumpLimit of them.
+func (c *SizedCache[K, V]) Dump() string {
+	summary := c.String()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return dumpKeys[K, *sizedEntry[K, V]](summary, c.lru, func(ent *sizedEntry[K, V]) K {
+		return ent.key
+	})
+}
+
+// dumpKeys formats summary followed by the keys of the entries of l, front to
+// back, eliding those past DumpLimit.
+func dumpKeys[K any, E any](summary string, l *list.List, key func(E) K) string {
+	var b strings.Builder
+	b.WriteString(summary)
+	i := 0
+	for elem := l.Front(); elem != nil; elem = elem.Next() {
+		if i == DumpLimit {
+			fmt.Fprintf(&b, "\n... %d more", l.Len()-DumpLimit)
+			break
+		}
+		fmt.Fprintf(&b, "\n%d: %v", i, key(elem.Value.(E)))
+		i++
+	}
+	return b.String()
+}
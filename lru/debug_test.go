@@ -0,0 +1,51 @@
+package lru
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheString(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCache[string, int](4)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	c.Get("a")
+	c.Get("missing")
+
+	require.Equal("lru.Cache{len=3 cap=4 fill=75.0% hits=1 misses=1 evictions=0}", c.String())
+	require.Equal(strings.Join([]string{
+		c.String(),
+		"0: a",
+		"1: c",
+		"2: b",
+	}, "\n"), c.Dump())
+}
+
+func TestCacheDumpLimit(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCache[int, int](2 * DumpLimit)
+	for i := range 2 * DumpLimit {
+		c.Put(i, i)
+	}
+	lines := strings.Split(c.Dump(), "\n")
+	require.Len(lines, DumpLimit+2)
+	require.Equal("0: 199", lines[1])
+	require.Equal("... 100 more", lines[len(lines)-1])
+}
+
+func TestSizedCacheString(t *testing.T) {
+	require := require.New(t)
+
+	c := NewSizedCache(10, func(_ string, v []byte) int { return len(v) })
+	c.Put("a", []byte("12"))
+	c.Put("b", []byte("345"))
+
+	require.Equal("lru.SizedCache{len=2 size=5 cap=10 fill=50.0%}", c.String())
+	require.Equal(c.String()+"\n0: b\n1: a", c.Dump())
+}
@@ -0,0 +1,28 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+// NewDeterministic creates an LRU cache whose behavior depends only on the
+// sequence of operations performed on it, so that tests can assert exactly
+// which entries it evicts.
+//
+// Wherever the cache would otherwise choose arbitrarily it picks the earliest
+// inserted entry, as ordered by InsertionOrder:
+//   - under Options.Priority, among candidates of equal priority, the
+//     earliest inserted one is evicted rather than the least recently used;
+//   - under Options.Weight, the victim is drawn from the least recently used
+//     entries with a fixed seed rather than from a random sample;
+//   - Sample returns the earliest inserted keys instead of relying on map
+//     iteration order.
+//
+// LazyPromotion is disabled, as the reads it buffers are applied in an order
+// that depends on goroutine scheduling and are dropped when the buffer is
+// full. Concurrent callers still race to perform their operations, so the
+// outcome is only reproducible for a reproducible order of calls.
+func NewDeterministic[K comparable, V any](size int) *Cache[K, V] {
+	return NewCacheWithOptions(Options[K, V]{
+		Size:          size,
+		Deterministic: true,
+	})
+}
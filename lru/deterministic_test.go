@@ -0,0 +1,75 @@
+package lru
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// runDeterministic applies a pseudo-random sequence of operations, fixed by
+// seed, to a deterministic cache and returns the keys it evicted and sampled.
+func runDeterministic(seed uint64) (evicted []int, sampled []int) {
+	c := NewCacheWithOptions(Options[int, int]{
+		Size:          16,
+		Deterministic: true,
+		LazyPromotion: true,
+		Priority: func(_, v int) int {
+			return v % 3
+		},
+		OnEvict: func(k, _ int) {
+			evicted = append(evicted, k)
+		},
+	})
+	r := rand.New(rand.NewPCG(seed, seed))
+	for range 1000 {
+		key := r.IntN(64)
+		if r.IntN(2) == 0 {
+			c.Put(key, r.IntN(100))
+		} else {
+			c.Get(key)
+		}
+	}
+	return evicted, c.Sample(8)
+}
+
+func TestDeterministicReproducible(t *testing.T) {
+	require := require.New(t)
+
+	evicted, sampled := runDeterministic(1)
+	require.NotEmpty(evicted)
+	require.Len(sampled, 8)
+
+	for range 3 {
+		evictedAgain, sampledAgain := runDeterministic(1)
+		require.Equal(evicted, evictedAgain)
+		require.Equal(sampled, sampledAgain)
+	}
+}
+
+func TestDeterministicTies(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCacheWithOptions(Options[int, int]{
+		Size:          3,
+		Deterministic: true,
+		Priority: func(int, int) int {
+			return 0
+		},
+	})
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Put(3, 3)
+	c.Get(1)
+
+	// Every entry has the same priority, so the earliest inserted one is
+	// evicted even though it was used most recently.
+	c.Put(4, 4)
+	require.False(c.Contains(1))
+	require.True(c.Contains(2))
+	require.True(c.Contains(3))
+
+	require.Equal([]int{2, 3}, c.Sample(2))
+	require.Equal([]int{2, 3, 4}, c.Sample(5))
+	require.Nil(c.reads)
+}
@@ -0,0 +1,44 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+// BumpEpoch invalidates every entry of the cache in O(1) and returns the new
+// epoch. Entries are tagged with the epoch the cache is in when they are
+// stored, and entries from an older epoch are stale: lookups report them as
+// missing, and writes treat them as absent. Processes caching the same data
+// can thus invalidate their copies after a shared change, such as a schema
+// upgrade, without clearing them key by key.
+//
+// Stale entries are removed lazily, when a lookup or write of their key comes
+// across them, which invokes the eviction callback as for expired entries.
+// Until then they still count towards Len, are listed by methods that don't
+// look up individual keys, such as All, Drain and EntryInfo, and are evicted
+// to make room as usual. A frozen cache keeps its epoch.
+func (c *Cache[K, V]) BumpEpoch() uint64 {
+	c.mu.Lock()
+	defer c.unlock()
+	if !c.rejectWrite() {
+		c.epoch++
+	}
+	return c.epoch
+}
+
+// Epoch returns the current epoch of the cache, which starts at 0 and is
+// incremented by BumpEpoch.
+func (c *Cache[K, V]) Epoch() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.epoch
+}
+
+// stale reports whether ent was stored in an older epoch.
+func (c *Cache[K, V]) stale(ent *entry[K, V]) bool {
+	return ent.epoch != c.epoch
+}
+
+// live reports whether ent may be returned by a lookup: it is neither stale
+// nor expired.
+func (c *Cache[K, V]) live(ent *entry[K, V]) bool {
+	return !c.stale(ent) && (ent.expiry.IsZero() || c.now().Before(ent.expiry))
+}
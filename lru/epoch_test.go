@@ -0,0 +1,56 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBumpEpoch(t *testing.T) {
+	require := require.New(t)
+
+	var evicted []int
+	c := NewCacheWithOnEvict(10, func(k, _ int) {
+		evicted = append(evicted, k)
+	})
+	for i := range 5 {
+		c.Put(i, i)
+	}
+	require.Zero(c.Epoch())
+
+	require.Equal(uint64(1), c.BumpEpoch())
+	require.Equal(uint64(1), c.Epoch())
+	for i := range 3 {
+		_, ok := c.Get(i)
+		require.False(ok)
+	}
+	require.Equal([]int{0, 1, 2}, evicted)
+	// The stale entries that weren't looked up are still held.
+	require.Equal(2, c.Len())
+
+	// Writes treat stale entries as absent.
+	require.False(c.CompareAndSwap(3, 3, 30, func(a, b int) bool { return a == b }))
+	c.Merge(3, 1, func(old, new int) int { return old + new })
+	require.Equal(1, c.GetOrZero(3))
+	require.True(c.PutVersioned(4, 40, 0))
+	require.Equal(40, c.GetOrZero(4))
+
+	// New entries are valid in the new epoch.
+	c.Put(5, 5)
+	v, ok := c.Get(5)
+	require.True(ok)
+	require.Equal(5, v)
+
+	c.BumpEpoch()
+	require.False(c.Contains(5))
+}
+
+func TestBumpEpochLazyPromotion(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCacheWithOptions(Options[int, int]{Size: 2, LazyPromotion: true})
+	c.Put(1, 1)
+	c.BumpEpoch()
+	_, ok := c.Get(1)
+	require.False(ok)
+}
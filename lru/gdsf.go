@@ -0,0 +1,107 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import "container/heap"
+
+// NewSizedCacheGDSF creates a size-bounded cache that evicts using
+// Greedy-Dual-Size-Frequency rather than LRU.
+//
+// Each entry is given a priority of clock + frequency*cost/size, where
+// frequency counts the Puts and Gets of the key, cost is reported by costFn
+// and size by sizeFn. Eviction removes the entry with the lowest priority and
+// advances the clock to that priority, so entries that are not accessed age
+// relative to newly inserted ones. This favors keeping entries that are
+// expensive to regenerate per byte of capacity they use.
+//
+// If costFn is nil, every entry has a cost of 1, which reduces to GDS
+// weighting small, frequently used entries.
+func NewSizedCacheGDSF[K comparable, V any](
+	maxSize int,
+	sizeFn func(K, V) int,
+	costFn func(K, V) float64,
+) *SizedCache[K, V] {
+	if costFn == nil {
+		costFn = func(K, V) float64 { return 1 }
+	}
+	c := NewSizedCache(maxSize, sizeFn)
+	c.gdsf = &gdsfPolicy[K, V]{costFn: costFn}
+	return c
+}
+
+type gdsfPolicy[K comparable, V any] struct {
+	costFn func(K, V) float64
+	clock  float64
+	queue  gdsfQueue[K, V]
+}
+
+func (p *gdsfPolicy[K, V]) add(key K, value V, entry *sizedEntry[K, V]) {
+	entry.cost = p.costFn(key, value)
+	entry.freq++
+	entry.priority = p.priority(entry)
+	heap.Push(&p.queue, entry)
+}
+
+func (p *gdsfPolicy[K, V]) touch(entry *sizedEntry[K, V]) {
+	entry.freq++
+	entry.priority = p.priority(entry)
+	heap.Fix(&p.queue, entry.index)
+}
+
+// resize updates the priority of an entry whose size changed.
+func (p *gdsfPolicy[K, V]) resize(entry *sizedEntry[K, V]) {
+	entry.priority = p.priority(entry)
+	heap.Fix(&p.queue, entry.index)
+}
+
+func (p *gdsfPolicy[K, V]) remove(entry *sizedEntry[K, V]) {
+	heap.Remove(&p.queue, entry.index)
+}
+
+// victim returns the lowest priority entry and advances the clock to its
+// priority. The entry is not removed.
+func (p *gdsfPolicy[K, V]) victim() (*sizedEntry[K, V], bool) {
+	if len(p.queue) == 0 {
+		return nil, false
+	}
+	entry := p.queue[0]
+	p.clock = entry.priority
+	return entry, true
+}
+
+func (p *gdsfPolicy[K, V]) reset() {
+	p.clock = 0
+	p.queue = nil
+}
+
+func (p *gdsfPolicy[K, V]) priority(entry *sizedEntry[K, V]) float64 {
+	return p.clock + float64(entry.freq)*entry.cost/float64(max(entry.size, 1))
+}
+
+// gdsfQueue is a min-heap of entries ordered by priority.
+type gdsfQueue[K comparable, V any] []*sizedEntry[K, V]
+
+func (q gdsfQueue[_, _]) Len() int           { return len(q) }
+func (q gdsfQueue[_, _]) Less(i, j int) bool { return q[i].priority < q[j].priority }
+
+func (q gdsfQueue[_, _]) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *gdsfQueue[K, V]) Push(x any) {
+	entry := x.(*sizedEntry[K, V])
+	entry.index = len(*q)
+	*q = append(*q, entry)
+}
+
+func (q *gdsfQueue[K, V]) Pop() any {
+	old := *q
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return entry
+}
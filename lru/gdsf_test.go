@@ -0,0 +1,118 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type costed struct {
+	size int
+	cost float64
+}
+
+func costedSize(_ int, v costed) int           { return v.size }
+func costedCost(_ int, v costed) float64       { return v.cost }
+func costedSizeStr(_ string, v costed) int     { return v.size }
+func costedCostStr(_ string, v costed) float64 { return v.cost }
+
+func TestGDSFEvictsLowestValuePerByte(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCacheGDSF[string, costed](10, costedSizeStr, costedCostStr)
+	cache.Put("cheap", costed{size: 4, cost: 1})
+	cache.Put("expensive", costed{size: 4, cost: 100})
+	// "expensive" is the least recently used entry, but "cheap" has the
+	// lowest priority and is evicted.
+	_, _ = cache.Get("cheap")
+	cache.Put("new", costed{size: 4, cost: 10})
+
+	_, ok := cache.Get("cheap")
+	require.False(ok)
+	_, ok = cache.Get("expensive")
+	require.True(ok)
+	_, ok = cache.Get("new")
+	require.True(ok)
+	require.Equal(2, cache.Len())
+}
+
+func TestGDSFFrequency(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCacheGDSF[string, costed](2, costedSizeStr, costedCostStr)
+	cache.Put("a", costed{size: 1, cost: 1})
+	cache.Put("b", costed{size: 1, cost: 1})
+	for i := 0; i < 3; i++ {
+		_, _ = cache.Get("a")
+	}
+	cache.Put("c", costed{size: 1, cost: 1})
+
+	_, ok := cache.Get("a")
+	require.True(ok)
+	_, ok = cache.Get("b")
+	require.False(ok)
+}
+
+func TestGDSFEvictAndFlush(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCacheGDSF[string, costed](10, costedSizeStr, nil)
+	cache.Put("a", costed{size: 3})
+	cache.Put("b", costed{size: 3})
+	cache.Evict("a")
+	require.Equal(1, cache.EvictFunc(func(string, costed) bool { return true }))
+	require.Zero(cache.Len())
+	require.Empty(cache.gdsf.queue)
+
+	cache.Put("c", costed{size: 3})
+	cache.Flush()
+	require.Empty(cache.gdsf.queue)
+	cache.Put("d", costed{size: 3})
+	require.Len(cache.gdsf.queue, 1)
+}
+
+func BenchmarkCostWeightedHitRate(b *testing.B) {
+	const (
+		numKeys = 10_000
+		maxSize = 100_000
+	)
+	r := rand.New(rand.NewSource(0))
+	values := make([]costed, numKeys)
+	for i := range values {
+		values[i] = costed{
+			size: 1 + r.Intn(100),
+			cost: float64(1 + r.Intn(100)),
+		}
+	}
+
+	constructors := map[string]func() *SizedCache[int, costed]{
+		"lru": func() *SizedCache[int, costed] {
+			return NewSizedCache[int, costed](maxSize, costedSize)
+		},
+		"gdsf": func() *SizedCache[int, costed] {
+			return NewSizedCacheGDSF[int, costed](maxSize, costedSize, costedCost)
+		},
+	}
+	for name, newCache := range constructors {
+		b.Run(name, func(b *testing.B) {
+			cache := newCache()
+			zipf := rand.NewZipf(rand.New(rand.NewSource(1)), 1.01, 1, numKeys-1)
+			var hitCost, totalCost float64
+			for i := 0; i < b.N; i++ {
+				key := int(zipf.Uint64())
+				value := values[key]
+				totalCost += value.cost
+				if _, ok := cache.Get(key); ok {
+					hitCost += value.cost
+					continue
+				}
+				cache.Put(key, value)
+			}
+			b.ReportMetric(hitCost/totalCost, "cost-hit-rate")
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package lru
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/cache"
+)
+
+func TestInlineCache(t *testing.T) {
+	require := require.New(t)
+
+	c := NewInlineCache[int, uint64](3)
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Put(3, 3)
+	require.Equal(1.0, c.PortionFilled())
+
+	v, ok := c.Get(1)
+	require.True(ok)
+	require.Equal(uint64(1), v)
+
+	// 2 is the least recently used entry.
+	c.Put(4, 4)
+	_, ok = c.Get(2)
+	require.False(ok)
+	require.Equal(3, c.Len())
+
+	c.Evict(3)
+	require.Equal(2, c.Len())
+	c.Put(5, 5)
+	c.Put(6, 6) // evicts 1
+	_, ok = c.Get(1)
+	require.False(ok)
+	for _, k := range []int{4, 5, 6} {
+		v, ok := c.Get(k)
+		require.True(ok)
+		require.Equal(uint64(k), v)
+	}
+
+	c.Flush()
+	require.Zero(c.Len())
+	require.Zero(c.PortionFilled())
+	c.Put(7, 7)
+	require.Equal(1, c.Len())
+}
+
+// TestInlineCacheMatchesCache checks InlineCache against Cache, which
+// implements the same LRU policy, under a random workload.
+func TestInlineCacheMatchesCache(t *testing.T) {
+	require := require.New(t)
+
+	const size = 16
+	var (
+		inline = NewInlineCache[int, int](size)
+		model  = NewCache[int, int](size)
+		r      = rand.New(rand.NewSource(0))
+	)
+	for range 10000 {
+		key := r.Intn(3 * size)
+		switch r.Intn(10) {
+		case 0:
+			inline.Evict(key)
+			model.Evict(key)
+		case 1, 2, 3:
+			inline.Put(key, key)
+			model.Put(key, key)
+		default:
+			got, gotOK := inline.Get(key)
+			want, wantOK := model.Get(key)
+			require.Equal(wantOK, gotOK)
+			require.Equal(want, got)
+		}
+		require.Equal(model.Len(), inline.Len())
+	}
+}
+
+// BenchmarkInlineCacheMemory reports the heap retained per entry by a full
+// InlineCache and a full Cache of uint64 keys and [32]byte values.
+func BenchmarkInlineCacheMemory(b *testing.B) {
+	const size = 1 << 20
+	for _, bc := range []struct {
+		name string
+		new  func() cache.Cacher[uint64, [32]byte]
+	}{
+		{name: "inline", new: func() cache.Cacher[uint64, [32]byte] { return NewInlineCache[uint64, [32]byte](size) }},
+		{name: "lru", new: func() cache.Cacher[uint64, [32]byte] { return NewCache[uint64, [32]byte](size) }},
+	} {
+		b.Run(fmt.Sprintf("%s/size=%d", bc.name, size), func(b *testing.B) {
+			var perEntry float64
+			for i := 0; i < b.N; i++ {
+				before := heapInUse()
+				c := bc.new()
+				for k := range uint64(size) {
+					c.Put(k, [32]byte{})
+				}
+				perEntry = float64(heapInUse()-before) / size
+				runtime.KeepAlive(c)
+			}
+			b.ReportMetric(perEntry, "B/entry")
+		})
+	}
+}
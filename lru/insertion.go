@@ -0,0 +1,41 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import (
+	"cmp"
+	"slices"
+)
+
+// InsertionOrder returns the keys of the cache from the earliest to the most
+// recently inserted, regardless of how they were accessed since, so that the
+// cache can double as a bounded log of recent items. Updating the value of an
+// existing key keeps its place, while a key that was removed and added again
+// counts as newly inserted.
+//
+// The order is tracked by an 8 byte sequence number in every entry. The keys
+// are sorted by it on each call, which costs O(n log n) with the lock held.
+func (c *Cache[K, V]) InsertionOrder() []K {
+	c.mu.Lock()
+	defer c.unlock()
+	return c.oldestInserted(len(c.items))
+}
+
+// oldestInserted returns the keys of the n earliest inserted entries, in
+// insertion order. Must be called with the lock held.
+func (c *Cache[K, V]) oldestInserted(n int) []K {
+	entries := make([]*entry[K, V], 0, len(c.items))
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		entries = append(entries, elem.Value.(*entry[K, V]))
+	}
+	slices.SortFunc(entries, func(a, b *entry[K, V]) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+
+	keys := make([]K, n)
+	for i := range keys {
+		keys[i] = entries[i].key
+	}
+	return keys
+}
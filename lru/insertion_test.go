@@ -0,0 +1,30 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertionOrder(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCache[int, int](4)
+	require.Empty(c.InsertionOrder())
+
+	for i := range 4 {
+		c.Put(i, i)
+	}
+	c.Get(0)
+	c.Get(2)
+	c.Put(1, 10)
+	require.Equal([]int{0, 1, 2, 3}, c.InsertionOrder())
+
+	// 3 is the least recently used entry, yet 0 stays the oldest inserted.
+	c.Put(4, 4)
+	require.Equal([]int{0, 1, 2, 4}, c.InsertionOrder())
+
+	c.Delete(0)
+	c.Put(0, 0)
+	require.Equal([]int{1, 2, 4, 0}, c.InsertionOrder())
+}
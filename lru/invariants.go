@@ -0,0 +1,161 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrInvariantViolated is wrapped by the errors returned by CheckInvariants.
+var ErrInvariantViolated = errors.New("lru: invariant violated")
+
+// CheckInvariants verifies the consistency of the cache's internal structures,
+// returning an error describing the first violation found. It walks every
+// entry with the lock held and is meant for tests, such as fuzz tests that
+// check the cache after random sequences of operations, not for production
+// code paths.
+func (c *Cache[K, V]) CheckInvariants() error {
+	c.mu.Lock()
+	defer c.unlock()
+	c.applyReads()
+
+	if sealed := c.sealedEntries(); sealed != nil {
+		if len(c.items) != 0 || c.lru.Len() != 0 {
+			return fmt.Errorf("%w: sealed cache has a recency list", ErrInvariantViolated)
+		}
+		if n := atomic.LoadInt64(&c.length); n != int64(len(sealed)) {
+			return fmt.Errorf("%w: length is %d but %d entries are sealed", ErrInvariantViolated, n, len(sealed))
+		}
+		return nil
+	}
+	if err := checkList(c.lru, len(c.items)); err != nil {
+		return err
+	}
+	if n := atomic.LoadInt64(&c.length); n != int64(len(c.items)) {
+		return fmt.Errorf("%w: length is %d but the map holds %d entries", ErrInvariantViolated, n, len(c.items))
+	}
+
+	var (
+		pinned   int
+		prevFreq uint64
+		groups   int
+	)
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		ent, ok := elem.Value.(*entry[K, V])
+		if !ok || ent == nil {
+			return fmt.Errorf("%w: list element holds %T", ErrInvariantViolated, elem.Value)
+		}
+		if c.items[ent.key] != elem {
+			return fmt.Errorf("%w: map entry of key %v is not its list element", ErrInvariantViolated, ent.key)
+		}
+		if ent.pinned {
+			pinned++
+		}
+		if ent.seq == 0 || ent.seq > c.seq {
+			return fmt.Errorf("%w: key %v has sequence number %d of %d", ErrInvariantViolated, ent.key, ent.seq, c.seq)
+		}
+		if c.policy != PolicyLFU {
+			continue
+		}
+
+		// Under PolicyLFU the list is ordered by decreasing frequency, and
+		// each group points at the frontmost element of its frequency.
+		if ent.freq == 0 {
+			return fmt.Errorf("%w: key %v has frequency 0", ErrInvariantViolated, ent.key)
+		}
+		if elem != c.lru.Front() && ent.freq > prevFreq {
+			return fmt.Errorf("%w: key %v has frequency %d after frequency %d", ErrInvariantViolated, ent.key, ent.freq, prevFreq)
+		}
+		if elem == c.lru.Front() || ent.freq != prevFreq {
+			if c.groups[ent.freq] != elem {
+				return fmt.Errorf("%w: group of frequency %d doesn't start at key %v", ErrInvariantViolated, ent.freq, ent.key)
+			}
+			groups++
+		}
+		prevFreq = ent.freq
+	}
+	if pinned != c.pinned {
+		return fmt.Errorf("%w: %d entries are pinned but %d are counted", ErrInvariantViolated, pinned, c.pinned)
+	}
+	if c.policy == PolicyLFU && groups != len(c.groups) {
+		return fmt.Errorf("%w: %d frequency groups are listed but %d are tracked", ErrInvariantViolated, groups, len(c.groups))
+	}
+	// The number of entries is not checked against the capacity: Resize
+	// keeps pinned entries, which may then be unpinned, leaving the cache
+	// above its capacity.
+	return nil
+}
+
+// CheckInvariants verifies the consistency of the cache's internal structures
+// as [Cache.CheckInvariants] does.
+func (c *SizedCache[K, V]) CheckInvariants() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := checkList(c.lru, len(c.items)); err != nil {
+		return err
+	}
+	size := 0
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		ent, ok := elem.Value.(*sizedEntry[K, V])
+		if !ok || ent == nil {
+			return fmt.Errorf("%w: list element holds %T", ErrInvariantViolated, elem.Value)
+		}
+		if c.items[ent.key] != elem {
+			return fmt.Errorf("%w: map entry of key %v is not its list element", ErrInvariantViolated, ent.key)
+		}
+		size += ent.size
+	}
+	if size != c.currentSize {
+		return fmt.Errorf("%w: entries total %d but the size is %d", ErrInvariantViolated, size, c.currentSize)
+	}
+	if c.currentSize > c.maxSize {
+		return fmt.Errorf("%w: size %d exceeds the capacity of %d", ErrInvariantViolated, c.currentSize, c.maxSize)
+	}
+	if c.maxEntries > 0 && len(c.items) > c.maxEntries {
+		return fmt.Errorf("%w: %d entries exceed the limit of %d", ErrInvariantViolated, len(c.items), c.maxEntries)
+	}
+	if c.gdsf == nil {
+		return nil
+	}
+
+	queue := c.gdsf.queue
+	if len(queue) != len(c.items) {
+		return fmt.Errorf("%w: %d entries are queued but the map holds %d", ErrInvariantViolated, len(queue), len(c.items))
+	}
+	for i, ent := range queue {
+		if ent.index != i {
+			return fmt.Errorf("%w: key %v is queued at %d but indexed at %d", ErrInvariantViolated, ent.key, i, ent.index)
+		}
+		if elem, ok := c.items[ent.key]; !ok || elem.Value != ent {
+			return fmt.Errorf("%w: queued key %v is not in the map", ErrInvariantViolated, ent.key)
+		}
+		if parent := (i - 1) / 2; i > 0 && queue.Less(i, parent) {
+			return fmt.Errorf("%w: key %v has a lower priority than its parent", ErrInvariantViolated, ent.key)
+		}
+	}
+	return nil
+}
+
+// checkList verifies that l links n elements consistently in both directions.
+func checkList(l *list.List, n int) error {
+	if l.Len() != n {
+		return fmt.Errorf("%w: list holds %d elements but the map holds %d", ErrInvariantViolated, l.Len(), n)
+	}
+	forward := 0
+	for elem := l.Front(); elem != nil && forward <= n; elem = elem.Next() {
+		forward++
+	}
+	backward := 0
+	for elem := l.Back(); elem != nil && backward <= n; elem = elem.Prev() {
+		backward++
+	}
+	if forward != n || backward != n {
+		return fmt.Errorf("%w: list links %d elements forward and %d backward, want %d", ErrInvariantViolated, forward, backward, n)
+	}
+	return nil
+}
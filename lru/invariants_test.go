@@ -0,0 +1,111 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fuzzCaches returns one cache per configuration exercised by the fuzz tests.
+func fuzzCaches() map[string]*Cache[byte, byte] {
+	return map[string]*Cache[byte, byte]{
+		"lru":  NewCache[byte, byte](8),
+		"fifo": NewCacheWithPolicy[byte, byte](8, PolicyFIFO),
+		"lfu":  NewLFUCacheWithDecay[byte, byte](8, LFUDecay{Every: 16}),
+		"lazy": NewCacheWithOptions(Options[byte, byte]{
+			Size:          8,
+			LazyPromotion: true,
+			Priority:      func(k, _ byte) int { return int(k % 3) },
+		}),
+	}
+}
+
+func FuzzCacheInvariants(f *testing.F) {
+	f.Add([]byte{0, 1, 0, 2, 1, 1, 3, 2, 4, 1})
+	f.Add([]byte{0, 1, 0, 2, 0, 3, 5, 1, 6, 2, 7, 0, 8, 9})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		for name, c := range fuzzCaches() {
+			for i := 0; i+1 < len(ops); i += 2 {
+				key := ops[i+1] % 16
+				switch ops[i] % 12 {
+				case 0:
+					c.Put(key, key)
+				case 1:
+					c.Get(key)
+				case 2:
+					c.Delete(key)
+				case 3:
+					c.Pin(key)
+				case 4:
+					c.Unpin(key)
+				case 5:
+					c.Resize(int(key%10) + 1)
+				case 6:
+					c.FlushColdest(float64(key) / 16)
+				case 7:
+					c.Merge(key, 1, func(old, new byte) byte { return old + new })
+				case 8:
+					c.BumpEpoch()
+				case 9:
+					c.GetOrdered([]byte{key, key + 1})
+				case 10:
+					c.Touch(key)
+				case 11:
+					c.EvictOldest(int(key % 4))
+				}
+				require.NoError(t, c.CheckInvariants(), "%s after op %d", name, i/2)
+			}
+		}
+	})
+}
+
+func FuzzSizedCacheInvariants(f *testing.F) {
+	f.Add([]byte{0, 1, 0, 2, 1, 1, 3, 2, 4, 1})
+	f.Add([]byte{0, 15, 0, 31, 5, 7, 0, 3, 2, 3})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		sizeOf := func(_, v byte) int { return int(v % 8) }
+		caches := map[string]*SizedCache[byte, byte]{
+			"lru":    NewSizedCache(16, sizeOf),
+			"limits": NewSizedCacheWithLimits(16, 4, sizeOf),
+			"gdsf":   NewSizedCacheGDSF(16, sizeOf, nil),
+		}
+		for name, c := range caches {
+			for i := 0; i+1 < len(ops); i += 2 {
+				arg := ops[i+1]
+				key := arg % 16
+				switch ops[i] % 6 {
+				case 0:
+					c.Put(key, arg)
+				case 1:
+					c.Get(key)
+				case 2:
+					c.Evict(key)
+				case 3:
+					c.Reserve(int(arg%20), int(arg%3))
+				case 4:
+					c.EvictFunc(func(k, _ byte) bool { return k == key })
+				case 5:
+					c.DelGet(key)
+				}
+				require.NoError(t, c.CheckInvariants(), "%s after op %d", name, i/2)
+			}
+		}
+	})
+}
+
+func TestCheckInvariantsDetectsCorruption(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCache[int, int](4)
+	c.Put(1, 1)
+	c.Put(2, 2)
+	require.NoError(c.CheckInvariants())
+	c.length++
+	require.ErrorIs(c.CheckInvariants(), ErrInvariantViolated)
+
+	s := NewSizedCache(10, func(_, v int) int { return v })
+	s.Put(1, 3)
+	require.NoError(s.CheckInvariants())
+	s.currentSize++
+	require.ErrorIs(s.CheckInvariants(), ErrInvariantViolated)
+}
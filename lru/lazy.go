@@ -0,0 +1,87 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import (
+	"container/list"
+	"sync/atomic"
+)
+
+// readBufferSize is the number of reads a lazily promoting cache records
+// before they are applied.
+const readBufferSize = 64
+
+// readBuffer records the elements read by lazy Gets, which hold the read
+// lock, until they are applied by a goroutine holding the write lock. Reads
+// arriving while the buffer is full are dropped.
+type readBuffer struct {
+	next  atomic.Uint64
+	slots [readBufferSize]atomic.Pointer[list.Element]
+}
+
+// record adds elem to the buffer and reports whether it filled the last slot,
+// in which case the caller should apply the buffered reads.
+func (b *readBuffer) record(elem *list.Element) bool {
+	i := b.next.Add(1) - 1
+	if i >= readBufferSize {
+		return false
+	}
+	b.slots[i].Store(elem)
+	return i == readBufferSize-1
+}
+
+// getLazy is Get under lazy promotion. The lookup holds only the read lock and
+// the access is recorded to be applied later. Expired and stale entries are
+// reported as missing and left for a later write to remove.
+func (c *Cache[K, V]) getLazy(key K) (V, bool) {
+	c.mu.RLock()
+	elem, ok := c.items[key]
+	if !ok {
+		c.mu.RUnlock()
+		var zero V
+		return zero, false
+	}
+	ent := elem.Value.(*entry[K, V])
+	if !c.live(ent) {
+		c.mu.RUnlock()
+		var zero V
+		return zero, false
+	}
+	value := ent.value
+	full := c.reads.record(elem)
+	c.mu.RUnlock()
+
+	if full {
+		c.mu.Lock()
+		c.unlock()
+	}
+	return value, true
+}
+
+// applyReads applies the accesses recorded by lazy Gets. Must be called with
+// the write lock held.
+func (c *Cache[K, V]) applyReads() {
+	if c.reads == nil {
+		return
+	}
+	n := min(c.reads.next.Load(), readBufferSize)
+	for i := range n {
+		elem := c.reads.slots[i].Swap(nil)
+		if elem == nil {
+			continue
+		}
+		ent := elem.Value.(*entry[K, V])
+		// The entry may have been removed, or replaced by a new entry for
+		// the same key, since it was read.
+		if c.items[ent.key] != elem {
+			continue
+		}
+		if !c.frozen || !c.freezeOpts.FreezeOrder {
+			c.promote(elem)
+		}
+		ent.hits++
+		c.markAccessed(ent)
+	}
+	c.reads.next.Store(0)
+}
@@ -0,0 +1,128 @@
+package lru
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyPromotion(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCacheWithOptions(Options[int, int]{
+		Size:          3,
+		LazyPromotion: true,
+	})
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3)
+
+	v, ok := cache.Get(1)
+	require.True(ok)
+	require.Equal(1, v)
+	_, ok = cache.Get(4)
+	require.False(ok)
+
+	// The read of 1 is applied before the eviction, so 2 is evicted.
+	cache.Put(4, 4)
+	info, ok := cache.EntryInfo(1)
+	require.True(ok)
+	require.Equal(uint64(1), info.Hits)
+	require.False(cache.Contains(2))
+
+	stats := cache.Stats()
+	require.Equal(uint64(1), stats.Hits)
+	require.Equal(uint64(1), stats.Misses)
+}
+
+func TestLazyPromotionBufferFills(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCacheWithOptions(Options[int, int]{
+		Size:          2,
+		LazyPromotion: true,
+	})
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	for range readBufferSize {
+		_, _ = cache.Get(1)
+	}
+
+	// Filling the buffer applied the reads without any write.
+	require.Zero(cache.reads.next.Load())
+	info, ok := cache.EntryInfo(1)
+	require.True(ok)
+	require.Zero(info.Position)
+	require.Equal(uint64(readBufferSize), info.Hits)
+}
+
+func TestLazyPromotionSkipsReplacedEntries(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCacheWithOptions(Options[int, int]{
+		Size:          2,
+		LazyPromotion: true,
+	})
+	cache.Put(1, 1)
+	_, _ = cache.Get(1)
+	cache.Evict(1)
+	cache.Put(1, 10)
+
+	info, ok := cache.EntryInfo(1)
+	require.True(ok)
+	require.Zero(info.Hits)
+}
+
+func TestLazyPromotionConcurrent(t *testing.T) {
+	cache := NewCacheWithOptions(Options[int, int]{
+		Size:          64,
+		LazyPromotion: true,
+	})
+
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(w)))
+			for range 10000 {
+				k := r.Intn(128)
+				if r.Intn(4) == 0 {
+					cache.Put(k, k)
+				} else if v, ok := cache.Get(k); ok && v != k {
+					t.Errorf("Get(%d) = %d", k, v)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	require.LessOrEqual(t, cache.Len(), 64)
+}
+
+// BenchmarkConcurrentGet compares the read throughput of exact and lazy
+// promotion under parallel readers of a warm cache.
+func BenchmarkConcurrentGet(b *testing.B) {
+	const size = 1024
+	for _, lazy := range []bool{false, true} {
+		b.Run(fmt.Sprintf("lazy=%t", lazy), func(b *testing.B) {
+			cache := NewCacheWithOptions(Options[int, int]{
+				Size:          size,
+				LazyPromotion: lazy,
+			})
+			for i := range size {
+				cache.Put(i, i)
+			}
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					_, _ = cache.Get(i % size)
+					i++
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// ErrLoadTimeout is returned by GetWithTimeout when the value was not loaded
+// within the timeout.
+var ErrLoadTimeout = errors.New("lru: load timed out")
+
+// Loader loads the value of a key missing from a LoadingCache. It should stop
+// and return once ctx is done.
+type Loader[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// LoadingCache is a read-through cache that fills misses by calling a Loader.
+// Concurrent misses of the same key share a single load.
+type LoadingCache[K comparable, V any] struct {
+	cache  *Cache[K, V]
+	loader Loader[K, V]
+
+	mu    sync.Mutex
+	loads map[K]*load[V]
+}
+
+// load is a load in flight. value and err are written before done is closed.
+// waiters counts the callers still waiting for it, and is guarded by the
+// LoadingCache's mu.
+type load[V any] struct {
+	done    chan struct{}
+	value   V
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// NewLoadingCache creates a read-through cache holding up to size entries.
+func NewLoadingCache[K comparable, V any](size int, loader Loader[K, V]) *LoadingCache[K, V] {
+	return &LoadingCache[K, V]{
+		cache:  NewCache[K, V](size),
+		loader: loader,
+		loads:  make(map[K]*load[V]),
+	}
+}
+
+// Cache returns the underlying cache, for inspection and explicit writes.
+func (c *LoadingCache[K, V]) Cache() *Cache[K, V] {
+	return c.cache
+}
+
+// Get returns the value of key, loading it on a miss. Load errors are returned
+// to every caller sharing the load and are not cached.
+func (c *LoadingCache[K, V]) Get(key K) (V, error) {
+	if value, ok := c.cache.Get(key); ok {
+		return value, nil
+	}
+	l := c.start(key)
+	<-l.done
+	return l.value, l.err
+}
+
+// GetWithTimeout is like Get but gives up after timeout, returning
+// ErrLoadTimeout.
+//
+// The timeout only bounds this call's wait: the load keeps running for the
+// callers that started or joined it, whatever their own timeouts. Once every
+// caller waiting for a load has given up, its context is cancelled and later
+// callers start a new load. A loader that ignores its context is abandoned
+// rather than waited on.
+func (c *LoadingCache[K, V]) GetWithTimeout(key K, timeout time.Duration) (V, error) {
+	if value, ok := c.cache.Get(key); ok {
+		return value, nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	l := c.start(key)
+	select {
+	case <-l.done:
+		return l.value, l.err
+	case <-timer.C:
+		c.leave(key, l)
+		var zero V
+		return zero, fmt.Errorf("%w: after %s", ErrLoadTimeout, timeout)
+	}
+}
+
+// start returns the load in flight for key, starting one if there is none, and
+// counts the caller as waiting for it. A load finishing between the caller's
+// miss and start has already stored its value, so it is returned as a
+// completed load.
+func (c *LoadingCache[K, V]) start(key K) *load[V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if l, ok := c.loads[key]; ok {
+		l.waiters++
+		return l
+	}
+	c.cache.mu.Lock()
+	value, ok := c.cache.get(key)
+	c.cache.unlock()
+	if ok {
+		l := &load[V]{done: make(chan struct{}), value: value}
+		close(l.done)
+		return l
+	}
+	// The load runs detached from its callers, so that one giving up doesn't
+	// fail the others.
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &load[V]{
+		done:    make(chan struct{}),
+		err:     ErrComputePanicked,
+		waiters: 1,
+		cancel:  cancel,
+	}
+	c.loads[key] = l
+	go c.run(ctx, key, l)
+	return l
+}
+
+// leave stops counting a caller as waiting for l, cancelling l once no caller
+// waits for it.
+func (c *LoadingCache[K, V]) leave(key K, l *load[V]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if l.cancel == nil {
+		return
+	}
+	l.waiters--
+	if l.waiters > 0 {
+		return
+	}
+	l.cancel()
+	if c.loads[key] == l {
+		delete(c.loads, key)
+	}
+}
+
+func (c *LoadingCache[K, V]) run(ctx context.Context, key K, l *load[V]) {
+	defer func() {
+		// A panicking loader runs on its own goroutine, so there is no caller
+		// to propagate the panic to. Waiters receive ErrComputePanicked,
+		// wrapped with the panic value and stack.
+		if r := recover(); r != nil {
+			l.err = fmt.Errorf("%w: %v\n%s", ErrComputePanicked, r, debug.Stack())
+		}
+
+		c.mu.Lock()
+		if c.loads[key] == l {
+			delete(c.loads, key)
+		}
+		c.mu.Unlock()
+		l.cancel()
+		close(l.done)
+	}()
+
+	l.value, l.err = c.loader(ctx, key)
+	if l.err == nil {
+		c.cache.Put(key, l.value)
+	}
+}
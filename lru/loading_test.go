@@ -0,0 +1,181 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadingCacheGet(t *testing.T) {
+	require := require.New(t)
+
+	var loads atomic.Int64
+	errTest := errors.New("test")
+	c := NewLoadingCache(2, func(_ context.Context, key int) (int, error) {
+		loads.Add(1)
+		if key < 0 {
+			return 0, errTest
+		}
+		return key * 10, nil
+	})
+
+	v, err := c.Get(1)
+	require.NoError(err)
+	require.Equal(10, v)
+
+	v, err = c.Get(1)
+	require.NoError(err)
+	require.Equal(10, v)
+	require.Equal(int64(1), loads.Load())
+
+	_, err = c.Get(-1)
+	require.ErrorIs(err, errTest)
+	require.False(c.Cache().Contains(-1))
+}
+
+func TestLoadingCacheGetWithTimeout(t *testing.T) {
+	require := require.New(t)
+
+	release := make(chan struct{})
+	c := NewLoadingCache(2, func(_ context.Context, key int) (int, error) {
+		// Ignore the context to simulate a hung backend.
+		<-release
+		return key, nil
+	})
+
+	start := time.Now()
+	_, err := c.GetWithTimeout(1, 20*time.Millisecond)
+	require.ErrorIs(err, ErrLoadTimeout)
+	require.Less(time.Since(start), time.Second)
+
+	// The abandoned load is cancelled, and the next call starts another one,
+	// which times out too rather than hanging.
+	_, err = c.GetWithTimeout(1, 20*time.Millisecond)
+	require.ErrorIs(err, ErrLoadTimeout)
+
+	// Once the loader returns, its value is cached.
+	close(release)
+	v, err := c.Get(1)
+	require.NoError(err)
+	require.Equal(1, v)
+	require.True(c.Cache().Contains(1))
+}
+
+func TestLoadingCacheGetWithTimeoutCancelsLoader(t *testing.T) {
+	require := require.New(t)
+
+	cancelled := make(chan error, 1)
+	c := NewLoadingCache(2, func(ctx context.Context, _ int) (int, error) {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return 0, ctx.Err()
+	})
+
+	_, err := c.GetWithTimeout(1, 10*time.Millisecond)
+	require.ErrorIs(err, ErrLoadTimeout)
+	require.ErrorIs(<-cancelled, context.Canceled)
+	require.False(c.Cache().Contains(1))
+}
+
+func TestLoadingCacheGetWithTimeoutKeepsJoinedLoad(t *testing.T) {
+	require := require.New(t)
+
+	release := make(chan struct{})
+	c := NewLoadingCache(2, func(ctx context.Context, key int) (int, error) {
+		select {
+		case <-release:
+			return key, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	})
+
+	// A short timeout starts the load, and a Get joins it before it gives up.
+	started := make(chan error, 1)
+	go func() {
+		_, err := c.GetWithTimeout(1, 200*time.Millisecond)
+		started <- err
+	}()
+	require.Eventually(func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.loads) == 1
+	}, time.Second, time.Millisecond)
+
+	type result struct {
+		value int
+		err   error
+	}
+	joined := make(chan result, 1)
+	go func() {
+		v, err := c.Get(1)
+		joined <- result{v, err}
+	}()
+	require.Eventually(func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, l := range c.loads {
+			return l.waiters == 2
+		}
+		return false
+	}, time.Second, time.Millisecond)
+
+	require.ErrorIs(<-started, ErrLoadTimeout)
+	close(release)
+	r := <-joined
+	require.NoError(r.err)
+	require.Equal(1, r.value)
+}
+
+func TestLoadingCacheCoalesces(t *testing.T) {
+	require := require.New(t)
+
+	const callers = 8
+	var (
+		loads   atomic.Int64
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+		values  = make([]int, callers)
+		errs    = make([]error, callers)
+	)
+	c := NewLoadingCache(2, func(_ context.Context, key int) (int, error) {
+		loads.Add(1)
+		<-release
+		return key, nil
+	})
+
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = c.GetWithTimeout(1, time.Minute)
+		}()
+	}
+	require.Eventually(func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.loads) == 1
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	for i := range callers {
+		require.NoError(errs[i])
+		require.Equal(1, values[i])
+	}
+	require.Equal(int64(1), loads.Load())
+}
+
+func TestLoadingCachePanic(t *testing.T) {
+	c := NewLoadingCache(2, func(context.Context, int) (int, error) {
+		panic("boom")
+	})
+	_, err := c.Get(1)
+	require.ErrorIs(t, err, ErrComputePanicked)
+	require.ErrorContains(t, err, "boom")
+	require.ErrorContains(t, err, "TestLoadingCachePanic")
+}
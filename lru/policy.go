@@ -0,0 +1,180 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import (
+	"container/list"
+	"time"
+)
+
+// Policy selects which entry a Cache evicts when it is full.
+//
+// The policy also defines the order in which the cache enumerates its
+// entries: methods documented in terms of recency, such as All, Drain and
+// EntryInfo, follow the eviction order instead, from the entry that would be
+// evicted last to the one that would be evicted first.
+type Policy int
+
+const (
+	// PolicyLRU evicts the least recently used entry. Reads and writes of an
+	// entry make it the most recently used.
+	PolicyLRU Policy = iota
+	// PolicyLFU evicts the least frequently used entry, breaking ties by
+	// evicting the least recently used one. Every read or write of an entry
+	// increments its frequency, which starts at 1 when it is inserted and
+	// is forgotten once it is removed.
+	PolicyLFU
+	// PolicyFIFO evicts the oldest inserted entry. Accessing or updating an
+	// entry doesn't change the order.
+	PolicyFIFO
+)
+
+// LFUDecay configures how PolicyLFU ages the frequencies of its entries.
+//
+// Without decay, frequencies only grow, so a key that was popular long ago
+// outlives keys that are popular now. With decay, every frequency is halved,
+// and rounded down to no less than 1, once per Every accesses and once per
+// Interval. A once popular key that stops being accessed thus loses half its
+// advantage per cycle until it is as evictable as a new key. Halving walks
+// every entry, so cycles should be spaced at least a few times the cache size
+// apart. The zero value disables decay.
+type LFUDecay struct {
+	// Every, if positive, is the number of reads and writes between halvings.
+	Every int
+	// Interval, if positive, is the time between halvings. It is checked on
+	// access, so an idle cache is decayed on its next access for every
+	// interval that elapsed.
+	Interval time.Duration
+}
+
+// NewLFUCacheWithDecay creates a PolicyLFU cache whose frequencies age as
+// configured by decay.
+func NewLFUCacheWithDecay[K comparable, V any](size int, decay LFUDecay) *Cache[K, V] {
+	return NewCacheWithOptions(Options[K, V]{
+		Size:   size,
+		Policy: PolicyLFU,
+		Decay:  decay,
+	})
+}
+
+// NewCacheWithPolicy creates a cache that evicts according to policy. Unknown
+// policies behave like PolicyLRU.
+func NewCacheWithPolicy[K comparable, V any](size int, policy Policy) *Cache[K, V] {
+	return NewCacheWithOptions(Options[K, V]{
+		Size:   size,
+		Policy: policy,
+	})
+}
+
+// insert adds ent to the eviction order as a new entry.
+func (c *Cache[K, V]) insert(ent *entry[K, V]) *list.Element {
+	c.markUsed(ent)
+	if c.policy != PolicyLFU {
+		return c.lru.PushFront(ent)
+	}
+
+	c.maybeDecay()
+
+	// Every other entry has a frequency of at least 1, so the new entry goes
+	// in front of the oldest entries, or at the back if there are none.
+	ent.freq = 1
+	var elem *list.Element
+	if head, ok := c.groups[1]; ok {
+		elem = c.lru.InsertBefore(ent, head)
+	} else {
+		elem = c.lru.PushBack(ent)
+	}
+	c.groups[1] = elem
+	return elem
+}
+
+// promote records an access of elem.
+func (c *Cache[K, V]) promote(elem *list.Element) {
+	c.markUsed(elem.Value.(*entry[K, V]))
+	switch c.policy {
+	case PolicyFIFO:
+	case PolicyLFU:
+		// The list is ordered by decreasing frequency, then by decreasing
+		// recency. Move elem to the front of the next frequency group, which
+		// is right before its current group if there is none.
+		c.maybeDecay()
+		ent := elem.Value.(*entry[K, V])
+		c.leaveGroup(elem)
+		if head, ok := c.groups[ent.freq+1]; ok {
+			c.lru.MoveBefore(elem, head)
+		} else if head, ok := c.groups[ent.freq]; ok {
+			c.lru.MoveBefore(elem, head)
+		}
+		ent.freq++
+		c.groups[ent.freq] = elem
+	default:
+		c.lru.MoveToFront(elem)
+	}
+}
+
+// unlink removes elem from the eviction order.
+func (c *Cache[K, V]) unlink(elem *list.Element) {
+	if c.policy == PolicyLFU {
+		c.leaveGroup(elem)
+	}
+	c.lru.Remove(elem)
+}
+
+// leaveGroup removes elem from its frequency group under PolicyLFU, leaving its
+// position in the list unchanged.
+func (c *Cache[K, V]) leaveGroup(elem *list.Element) {
+	freq := elem.Value.(*entry[K, V]).freq
+	if c.groups[freq] != elem {
+		return
+	}
+	if next := elem.Next(); next != nil && next.Value.(*entry[K, V]).freq == freq {
+		c.groups[freq] = next
+	} else {
+		delete(c.groups, freq)
+	}
+}
+
+// resetOrder empties the eviction order.
+func (c *Cache[K, V]) resetOrder() {
+	c.lru.Init()
+	clear(c.groups)
+}
+
+// maybeDecay records an access under PolicyLFU and halves the frequencies of
+// every entry for each decay cycle that completed, before the access itself is
+// counted.
+func (c *Cache[K, V]) maybeDecay() {
+	halvings := 0
+	if c.decay.Every > 0 {
+		c.accesses++
+		if c.accesses >= c.decay.Every {
+			c.accesses = 0
+			halvings++
+		}
+	}
+	if c.decay.Interval > 0 {
+		if elapsed := c.now().Sub(c.lastDecay); elapsed >= c.decay.Interval {
+			cycles := elapsed / c.decay.Interval
+			c.lastDecay = c.lastDecay.Add(cycles * c.decay.Interval)
+			halvings += int(min(cycles, 64))
+		}
+	}
+	if halvings > 0 {
+		c.halveFrequencies(min(halvings, 64))
+	}
+}
+
+// halveFrequencies divides every frequency by 2^shift, keeping them at least
+// 1. The list stays ordered by decreasing frequency, so only the group heads
+// need to be recomputed.
+func (c *Cache[K, V]) halveFrequencies(shift int) {
+	clear(c.groups)
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		ent := elem.Value.(*entry[K, V])
+		ent.freq = max(ent.freq>>shift, 1)
+		if _, ok := c.groups[ent.freq]; !ok {
+			c.groups[ent.freq] = elem
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package lru
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyEviction(t *testing.T) {
+	tests := []struct {
+		policy  Policy
+		evicted int
+	}{
+		{policy: PolicyLRU, evicted: 2},
+		{policy: PolicyLFU, evicted: 3},
+		{policy: PolicyFIFO, evicted: 1},
+	}
+	for _, test := range tests {
+		cache := NewCacheWithPolicy[int, int](3, test.policy)
+		cache.Put(1, 1)
+		cache.Put(2, 2)
+		cache.Put(3, 3)
+		_, _ = cache.Get(2)
+		_, _ = cache.Get(2)
+		_, _ = cache.Get(3)
+		_, _ = cache.Get(1)
+
+		evicted, ok := cache.PutEvicting(4, 4)
+		require.True(t, ok)
+		require.Equal(t, test.evicted, evicted, "policy %d", test.policy)
+	}
+}
+
+func TestPolicyLFUMatchesModel(t *testing.T) {
+	require := require.New(t)
+
+	type modelEntry struct {
+		freq   int
+		access int
+	}
+	const (
+		size = 8
+		keys = 16
+	)
+	var (
+		rng   = rand.New(rand.NewSource(0))
+		cache = NewCacheWithPolicy[int, int](size, PolicyLFU)
+		model = make(map[int]modelEntry)
+	)
+	for tick := 0; tick < 10_000; tick++ {
+		key := rng.Intn(keys)
+		switch rng.Intn(3) {
+		case 0:
+			_, ok := cache.Get(key)
+			e, exists := model[key]
+			require.Equal(exists, ok)
+			if exists {
+				model[key] = modelEntry{freq: e.freq + 1, access: tick}
+			}
+		case 1:
+			cache.Delete(key)
+			delete(model, key)
+		default:
+			if e, exists := model[key]; exists {
+				cache.Put(key, key)
+				model[key] = modelEntry{freq: e.freq + 1, access: tick}
+				continue
+			}
+			victim, evicted := cache.PutEvicting(key, key)
+			require.Equal(len(model) == size, evicted)
+			if evicted {
+				expected := -1
+				for k, e := range model {
+					if best, ok := model[expected]; !ok || e.freq < best.freq || (e.freq == best.freq && e.access < best.access) {
+						expected = k
+					}
+				}
+				require.Equal(expected, victim)
+				delete(model, victim)
+			}
+			model[key] = modelEntry{freq: 1, access: tick}
+		}
+	}
+}
+
+func TestLFUDecayEvery(t *testing.T) {
+	require := require.New(t)
+
+	// Without decay, a key read 100 times outlives any number of newer keys
+	// read a few times each.
+	for _, decay := range []LFUDecay{{}, {Every: 20}} {
+		cache := NewLFUCacheWithDecay[int, int](3, decay)
+		cache.Put(0, 0)
+		for range 100 {
+			_, _ = cache.Get(0)
+		}
+		for k := 1; k <= 30; k++ {
+			cache.Put(k, k)
+			_, _ = cache.Get(k)
+			_, _ = cache.Get(k)
+		}
+		require.Equal(decay.Every == 0, cache.Contains(0), "decay %+v", decay)
+	}
+}
+
+func TestLFUDecayInterval(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(0, 0)
+	cache := NewCacheWithOptions(Options[int, int]{
+		Size:   2,
+		Policy: PolicyLFU,
+		Decay:  LFUDecay{Interval: time.Minute},
+		Clock:  func() time.Time { return now },
+	})
+	cache.Put(0, 0)
+	for range 64 {
+		_, _ = cache.Get(0)
+	}
+	cache.Put(1, 1)
+	_, _ = cache.Get(1)
+
+	// 0 has a frequency of 65 and 1 of 2, so 1 is evicted.
+	cache.Put(2, 2)
+	_, ok := cache.EntryInfo(1)
+	require.False(ok)
+
+	// After seven idle intervals, 0 is halved down to 1, and the next access
+	// makes 2 the more frequent entry.
+	now = now.Add(7 * time.Minute)
+	_, _ = cache.Get(2)
+	cache.Put(3, 3)
+	require.False(cache.Contains(0))
+	require.True(cache.Contains(2))
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import "container/list"
+
+// PriorityWindow is the number of least recently used entries a priority
+// cache considers when choosing which entry to evict.
+//
+// A larger window lets priority override recency further: an entry survives
+// eviction as long as a lower-priority entry is among the PriorityWindow
+// entries closest to eviction. With a window of 1 the cache is a plain LRU.
+// Each eviction calls the priority function once per candidate, so the cost
+// of a full cache's Put grows linearly with the window.
+const PriorityWindow = 8
+
+// NewPriorityCache creates an LRU cache whose evictions are steered by
+// priority. When the cache is full, the entry with the lowest priority among
+// the PriorityWindow least recently used entries is evicted, the least
+// recently used of them winning ties. Entries outside that window are never
+// evicted ahead of it, however low their priority.
+//
+// priority is called with the cache's lock held and must not call back into
+// the cache. It may be called many times for the same entry, so it should be
+// cheap and return the same result for the same key and value.
+func NewPriorityCache[K comparable, V any](size int, priority func(K, V) int) *Cache[K, V] {
+	return NewCacheWithOptions(Options[K, V]{
+		Size:     size,
+		Priority: priority,
+	})
+}
+
+// priorityVictim returns the lowest-priority unpinned element among the
+// PriorityWindow unpinned elements starting at the least recently used one,
+// oldest.
+func (c *Cache[K, V]) priorityVictim(oldest *list.Element) *list.Element {
+	var (
+		ent      = oldest.Value.(*entry[K, V])
+		victim   = oldest
+		lowest   = c.priority(ent.key, ent.value)
+		compared = 1
+	)
+	for elem := oldest.Prev(); elem != nil && compared < PriorityWindow; elem = elem.Prev() {
+		ent := elem.Value.(*entry[K, V])
+		if ent.pinned {
+			continue
+		}
+		compared++
+		p := c.priority(ent.key, ent.value)
+		if p < lowest || p == lowest && c.deterministic && ent.seq < victim.Value.(*entry[K, V]).seq {
+			victim, lowest = elem, p
+		}
+	}
+	return victim
+}
@@ -0,0 +1,79 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityCacheKeepsHighPriority(t *testing.T) {
+	require := require.New(t)
+
+	// Even keys are canonical and outrank odd ones.
+	priority := func(k, _ int) int {
+		if k%2 == 0 {
+			return 1
+		}
+		return 0
+	}
+	cache := NewPriorityCache(4, priority)
+	cache.Put(0, 0)
+	cache.Put(2, 2)
+	cache.Put(1, 1)
+	cache.Put(3, 3)
+	// Warm the low-priority entries so the high-priority ones are coldest.
+	_, _ = cache.Get(1)
+	_, _ = cache.Get(3)
+
+	cache.Put(5, 5)
+	require.True(cache.Contains(0))
+	require.True(cache.Contains(2))
+	require.False(cache.Contains(1))
+
+	cache.Put(7, 7)
+	require.True(cache.Contains(0))
+	require.True(cache.Contains(2))
+	require.False(cache.Contains(3))
+	require.Equal(uint64(2), cache.Stats().Evictions)
+}
+
+func TestPriorityCacheTiesEvictLeastRecent(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewPriorityCache(2, func(int, int) int { return 0 })
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3)
+	require.False(cache.Contains(1))
+	require.True(cache.Contains(2))
+	require.True(cache.Contains(3))
+}
+
+func TestPriorityCacheWindow(t *testing.T) {
+	require := require.New(t)
+
+	// Key 1 has the lowest priority but is the most recently used, outside
+	// the window, so the lowest priority within the window is evicted.
+	size := PriorityWindow + 1
+	cache := NewPriorityCache(size, func(k, _ int) int { return k })
+	for k := size; k > 0; k-- {
+		cache.Put(k, k)
+	}
+	cache.Put(0, 0)
+	require.True(cache.Contains(1))
+	require.False(cache.Contains(2))
+	require.Equal(size, cache.Len())
+}
+
+func TestPriorityCacheSkipsPinned(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewPriorityCache(2, func(k, _ int) int { return k })
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	require.True(cache.Pin(1))
+
+	cache.Put(3, 3)
+	require.True(cache.Contains(1))
+	require.False(cache.Contains(2))
+}
@@ -0,0 +1,107 @@
+package lru
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/cache"
+)
+
+func TestRingCache(t *testing.T) {
+	tests := []struct {
+		policy   Policy
+		survivor int
+		evicted  int
+	}{
+		{policy: PolicyLRU, survivor: 1, evicted: 2},
+		{policy: PolicyFIFO, survivor: 2, evicted: 1},
+	}
+	for _, test := range tests {
+		require := require.New(t)
+
+		c := NewRingCache[int, int](2, test.policy)
+		c.Put(1, 1)
+		c.Put(2, 2)
+		v, ok := c.Get(1)
+		require.True(ok)
+		require.Equal(1, v)
+
+		c.Put(3, 3)
+		require.Equal(2, c.Len())
+		_, ok = c.Get(test.evicted)
+		require.False(ok)
+		_, ok = c.Get(test.survivor)
+		require.True(ok)
+		_, ok = c.Get(3)
+		require.True(ok)
+	}
+}
+
+func TestRingCacheEvictAndFlush(t *testing.T) {
+	require := require.New(t)
+
+	c := NewRingCache[int, int](4, PolicyLRU)
+	for i := 0; i < 4; i++ {
+		c.Put(i, i)
+	}
+	c.Put(2, 20)
+	require.Equal(4, c.Len())
+	require.Equal(1.0, c.PortionFilled())
+
+	c.Evict(0)
+	c.Evict(7)
+	require.Equal(3, c.Len())
+	_, ok := c.Get(0)
+	require.False(ok)
+	v, ok := c.Get(2)
+	require.True(ok)
+	require.Equal(20, v)
+
+	c.Flush()
+	require.Zero(c.Len())
+	_, ok = c.Get(2)
+	require.False(ok)
+}
+
+func TestRingCacheAllocs(t *testing.T) {
+	c := NewRingCache[int, int](8, PolicyLRU)
+	allocs := testing.AllocsPerRun(100, func() {
+		for i := 0; i < 16; i++ {
+			c.Put(i, i)
+			_, _ = c.Get(i - 4)
+		}
+	})
+	require.Zero(t, allocs)
+}
+
+func BenchmarkRingCache(b *testing.B) {
+	for _, size := range []int{4, 16, 64, 128, 256} {
+		// Keys span 25% more than the capacity, so most Gets hit.
+		rng := rand.New(rand.NewSource(0))
+		keys := make([]int, 1024)
+		for i := range keys {
+			keys[i] = rng.Intn(size + size/4 + 1)
+		}
+
+		caches := []struct {
+			name  string
+			cache cache.Cacher[int, int]
+		}{
+			{name: "ring", cache: NewRingCache[int, int](size, PolicyLRU)},
+			{name: "lru", cache: NewCache[int, int](size)},
+		}
+		for _, c := range caches {
+			b.Run(fmt.Sprintf("size=%d/%s", size, c.name), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					key := keys[i%len(keys)]
+					if _, ok := c.cache.Get(key); !ok {
+						c.cache.Put(key, key)
+					}
+				}
+			})
+		}
+	}
+}
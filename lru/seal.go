@@ -0,0 +1,69 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import "sync/atomic"
+
+// Seal replaces the contents of the cache with entries and makes it read-only
+// for the rest of its lifetime, for reference data that never changes once
+// loaded. Unlike Freeze, Seal also drops the recency list and the per-entry
+// metadata: the cache becomes a plain map of entries, which takes less memory
+// and is read without locking or promoting anything. The entries held before
+// are removed and passed to the eviction callback as by Clear.
+//
+// After Seal, the cache behaves as if frozen: every mutation is rejected as
+// described by Freeze, including another Seal. Capacity no longer applies:
+// every entry is kept, and Cap reports their number. Get, GetOrDefault,
+// GetOrZero, GetOrdered, GetVersioned, GetOrCompute, Contains, All and Sample
+// read the sealed entries, whose version is 0, but nothing records their
+// access: hits are counted by Stats, not by EntryInfo. Methods reporting
+// recency, insertion or eviction order, such as Oldest, Newest, EntryInfo,
+// InsertionOrder and Dump, find no entries.
+//
+// entries is copied, so the caller may reuse it. If the cache copies values,
+// as configured by Options.Clone, each value is cloned on the way in.
+func (c *Cache[K, V]) Seal(entries map[K]V) {
+	sealed := make(map[K]V, len(entries))
+	for key, value := range entries {
+		if c.clone != nil {
+			value = c.clone(value)
+		}
+		sealed[key] = value
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+	if c.rejectWrite() {
+		return
+	}
+	c.applyReads()
+	if c.onEvict != nil {
+		for e := c.lru.Back(); e != nil; e = e.Prev() {
+			c.evicted = append(c.evicted, e.Value.(*entry[K, V]))
+		}
+	}
+	c.items = nil
+	c.groups = nil
+	c.pinned = 0
+	c.resetOrder()
+	atomic.StoreInt64(&c.length, int64(len(sealed)))
+	atomic.StoreInt64(&c.capacity, int64(len(sealed)))
+	c.frozen = true
+	c.sealed.Store(&sealed)
+	c.emitFlush()
+}
+
+// Sealed reports whether Seal has been called.
+func (c *Cache[K, V]) Sealed() bool {
+	return c.sealed.Load() != nil
+}
+
+// sealedEntries returns the entries of a sealed cache, or nil if the cache
+// isn't sealed.
+func (c *Cache[K, V]) sealedEntries() map[K]V {
+	if m := c.sealed.Load(); m != nil {
+		return *m
+	}
+	return nil
+}
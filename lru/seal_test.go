@@ -0,0 +1,107 @@
+package lru
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeal(t *testing.T) {
+	require := require.New(t)
+
+	var evicted []int
+	c := NewCacheWithOnEvict(2, func(k, _ int) { evicted = append(evicted, k) })
+	c.Put(-1, -1)
+	require.False(c.Sealed())
+
+	entries := map[int]int{1: 10, 2: 20, 3: 30, 4: 40}
+	c.Seal(entries)
+	entries[5] = 50
+	require.True(c.Sealed())
+	require.True(c.Frozen())
+	require.Equal([]int{-1}, evicted)
+
+	// Every entry is kept, whatever the capacity.
+	require.Equal(4, c.Len())
+	require.Equal(4, c.Cap())
+	require.Equal(1.0, c.PortionFilled())
+	for k := 1; k <= 4; k++ {
+		v, ok := c.Get(k)
+		require.True(ok)
+		require.Equal(10*k, v)
+	}
+	_, ok := c.Get(5)
+	require.False(ok)
+	require.False(c.Contains(-1))
+	require.True(c.Contains(3))
+	values, found := c.GetOrdered([]int{2, 5})
+	require.Equal([]int{20, 0}, values)
+	require.Equal([]bool{true, false}, found)
+	v, version, ok := c.GetVersioned(4)
+	require.True(ok)
+	require.Equal(40, v)
+	require.Zero(version)
+	require.Equal(map[int]int{1: 10, 2: 20, 3: 30, 4: 40}, maps.Collect(c.All()))
+	require.Len(c.Sample(10), 4)
+	stats := c.Stats()
+	require.Equal(uint64(6), stats.Hits)
+	require.Equal(uint64(2), stats.Misses)
+
+	// The recency structure is gone.
+	require.Empty(c.items)
+	require.Zero(c.lru.Len())
+	_, _, ok = c.Oldest()
+	require.False(ok)
+	require.NoError(c.CheckInvariants())
+
+	// Writes are rejected.
+	c.Put(1, 0)
+	c.Put(6, 60)
+	c.Delete(2)
+	c.Clear()
+	c.Seal(map[int]int{7: 70})
+	require.Equal(4, c.Len())
+	v, ok = c.Get(1)
+	require.True(ok)
+	require.Equal(10, v)
+	require.False(c.Contains(6))
+	require.False(c.Contains(7))
+	require.True(c.Contains(2))
+	require.Equal([]int{-1}, evicted)
+}
+
+func TestSealPanicOnWrite(t *testing.T) {
+	c := NewCache[int, int](2)
+	c.Seal(map[int]int{1: 1})
+	c.FreezeWithOptions(FreezeOptions{PanicOnWrite: true})
+	require.Panics(t, func() { c.Put(2, 2) })
+	require.Equal(t, 1, c.GetOrZero(1))
+}
+
+func BenchmarkSealedGet(b *testing.B) {
+	entries := make(map[int]int, 1024)
+	for i := range 1024 {
+		entries[i] = i
+	}
+	for _, sealed := range []bool{false, true} {
+		name := "lru"
+		if sealed {
+			name = "sealed"
+		}
+		b.Run(name, func(b *testing.B) {
+			c := NewCache[int, int](len(entries))
+			if sealed {
+				c.Seal(entries)
+			} else {
+				for k, v := range entries {
+					c.Put(k, v)
+				}
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				c.Get(i & 1023)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import "container/list"
+
+// sentinelLane holds the keys known to have no value. It is bounded by its
+// own count and evicts the least recently used key when full.
+type sentinelLane[K comparable] struct {
+	max   int // 0 disables sentinels
+	items map[K]*list.Element
+	order *list.List // front is most recently used
+}
+
+func (l *sentinelLane[K]) add(key K) {
+	if l.max <= 0 {
+		return
+	}
+	if elem, ok := l.items[key]; ok {
+		l.order.MoveToFront(elem)
+		return
+	}
+	if l.items == nil {
+		l.items = make(map[K]*list.Element)
+		l.order = list.New()
+	}
+	if len(l.items) >= l.max {
+		oldest := l.order.Back()
+		delete(l.items, oldest.Value.(K))
+		l.order.Remove(oldest)
+	}
+	l.items[key] = l.order.PushFront(key)
+}
+
+func (l *sentinelLane[K]) get(key K) bool {
+	elem, ok := l.items[key]
+	if ok {
+		l.order.MoveToFront(elem)
+	}
+	return ok
+}
+
+func (l *sentinelLane[K]) remove(key K) bool {
+	elem, ok := l.items[key]
+	if ok {
+		delete(l.items, key)
+		l.order.Remove(elem)
+	}
+	return ok
+}
+
+func (l *sentinelLane[K]) reset() {
+	l.items = nil
+	l.order = nil
+}
+
+// NewSizedCacheWithSentinels creates a size-bounded LRU cache that can also
+// hold up to maxSentinels keys marked as known to have no value, see
+// PutSentinel.
+func NewSizedCacheWithSentinels[K comparable, V any](maxSize, maxSentinels int, sizeFn func(K, V) int) *SizedCache[K, V] {
+	c := NewSizedCache(maxSize, sizeFn)
+	c.sentinels.max = max(maxSentinels, 0)
+	return c
+}
+
+// PutSentinel marks key as known to have no value, replacing any value it
+// has. Sentinels are kept apart from the entries: they don't count towards
+// the size or entry bounds and never cause an entry to be evicted. Instead,
+// once the cache holds its maximum number of sentinels, the least recently
+// used sentinel is dropped. Storing a value for the key with Put clears its
+// sentinel.
+//
+// PutSentinel does nothing if the cache wasn't created with
+// NewSizedCacheWithSentinels.
+func (c *SizedCache[K, V]) PutSentinel(key K) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.sentinels.max <= 0 {
+		return
+	}
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+	c.sentinels.add(key)
+}
+
+// Lookup is like Get, but also reports whether the key is a sentinel. A
+// sentinel is reported as not found with sentinel set to true.
+func (c *SizedCache[K, V]) Lookup(key K) (value V, found bool, sentinel bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if value, found = c.get(key); found {
+		return value, true, false
+	}
+	return value, false, c.sentinels.get(key)
+}
+
+// SentinelLen returns the number of sentinels.
+func (c *SizedCache[K, V]) SentinelLen() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.sentinels.items)
+}
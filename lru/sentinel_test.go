@@ -0,0 +1,123 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizedCacheSentinels(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCacheWithSentinels(10, 3, func(_, v int) int { return v })
+	cache.Put(1, 5)
+	cache.Put(2, 5)
+
+	// Sentinels don't evict real entries.
+	for i := 10; i < 20; i++ {
+		cache.PutSentinel(i)
+	}
+	require.Equal(2, cache.Len())
+	require.Equal(10, cache.Size())
+
+	// and respect their own cap, dropping the least recently used.
+	require.Equal(3, cache.SentinelLen())
+	_, found, sentinel := cache.Lookup(16)
+	require.False(found)
+	require.False(sentinel)
+	for i := 17; i < 20; i++ {
+		_, found, sentinel = cache.Lookup(i)
+		require.False(found)
+		require.True(sentinel)
+	}
+
+	// Get reports a sentinel as a miss.
+	_, ok := cache.Get(17)
+	require.False(ok)
+
+	v, found, sentinel := cache.Lookup(1)
+	require.True(found)
+	require.False(sentinel)
+	require.Equal(5, v)
+
+	// A sentinel replaces a value, and a value replaces a sentinel.
+	cache.PutSentinel(1)
+	require.Equal(1, cache.Len())
+	_, _, sentinel = cache.Lookup(1)
+	require.True(sentinel)
+	cache.Put(19, 1)
+	_, found, sentinel = cache.Lookup(19)
+	require.True(found)
+	require.False(sentinel)
+
+	require.Equal(2, cache.SentinelLen())
+
+	require.Equal(1, cache.EvictMany([]int{18, 100}))
+	cache.Flush()
+	require.Zero(cache.SentinelLen())
+}
+
+func TestSizedCachePutOversizeClearsSentinel(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCacheWithSentinels(10, 3, func(_, v int) int { return v })
+	cache.PutSentinel(1)
+	cache.PutSentinel(2)
+
+	// The value is too large to store, but it still replaces the sentinel.
+	cache.Put(1, 11)
+	_, found, sentinel := cache.Lookup(1)
+	require.False(found)
+	require.False(sentinel)
+
+	_, _, sentinel = cache.Lookup(2)
+	require.True(sentinel)
+}
+
+func TestSizedCacheSentinelsDisabled(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCache[int, int](10, nil)
+	cache.PutSentinel(1)
+	require.Zero(cache.SentinelLen())
+	_, _, sentinel := cache.Lookup(1)
+	require.False(sentinel)
+}
+
+func TestSizedCacheLookupConsistent(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCacheWithSentinels(10, 3, func(_, v int) int { return v })
+	cache.PutSentinel(1)
+
+	// The key always holds either a value or a sentinel, so a lookup must
+	// see one of them.
+	var (
+		wg   sync.WaitGroup
+		stop = make(chan struct{})
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			cache.Put(1, 1)
+			cache.PutSentinel(1)
+		}
+	}()
+	for i := 0; i < 10_000; i++ {
+		_, found, sentinel := cache.Lookup(1)
+		if found == sentinel {
+			close(stop)
+			wg.Wait()
+			require.FailNow("inconsistent lookup", "found=%t sentinel=%t", found, sentinel)
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
@@ -0,0 +1,195 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import (
+	"hash/maphash"
+	"iter"
+
+	"github.com/luxfi/cache"
+)
+
+var (
+	_ cache.Cacher[struct{}, struct{}]   = (*ShardedSizedCache[struct{}, struct{}])(nil)
+	_ cache.Iterable[struct{}, struct{}] = (*ShardedSizedCache[struct{}, struct{}])(nil)
+	_ cache.SizeReporter                 = (*ShardedSizedCache[struct{}, struct{}])(nil)
+	_ cache.ByteSized                    = (*ShardedSizedCache[struct{}, struct{}])(nil)
+	_ cache.StatsReporter                = (*ShardedSizedCache[struct{}, struct{}])(nil)
+	_ cache.BoundReporter                = (*ShardedSizedCache[struct{}, struct{}])(nil)
+)
+
+// ShardedSizedCache spreads its entries over several [SizedCache] shards
+// chosen by key hash, so that concurrent writers to different keys mostly take
+// different locks. It is the generic counterpart of bytecache.Cache for values
+// other than byte slices.
+//
+// The total size is divided evenly across the shards, each evicting its own
+// least recently used entries once over its share, so the cache as a whole
+// only approximates LRU, and an entry larger than a shard's share is never
+// stored. Operations spanning the whole cache, such as Len, Flush and All,
+// visit the shards one at a time and are not atomic across them.
+type ShardedSizedCache[K comparable, V any] struct {
+	hash   func(K) uint64
+	shards []*SizedCache[K, V]
+}
+
+// NewShardedSizedCache creates a cache split into the given number of shards,
+// bounded by a total size of maxSize as computed by sizeFn. The size is
+// divided across the shards, the first shards receiving one more unit of
+// size each if it doesn't divide evenly, and a count below one is treated as
+// one. Each shard is at least of size 1, so a maxSize smaller than the number
+// of shards is rounded up to it.
+//
+// hash picks the shard of each key. It must return the same value for equal
+// keys and should spread keys evenly. A nil hash uses [maphash.Comparable]
+// with a random seed.
+func NewShardedSizedCache[K comparable, V any](
+	shards int,
+	maxSize int,
+	sizeFn func(K, V) int,
+	hash func(K) uint64,
+) *ShardedSizedCache[K, V] {
+	shards = max(shards, 1)
+	if hash == nil {
+		seed := maphash.MakeSeed()
+		hash = func(key K) uint64 {
+			return maphash.Comparable(seed, key)
+		}
+	}
+	c := &ShardedSizedCache[K, V]{
+		hash:   hash,
+		shards: make([]*SizedCache[K, V], shards),
+	}
+	perShard, extra := maxSize/shards, maxSize%shards
+	for i := range c.shards {
+		size := perShard
+		if i < extra {
+			size++
+		}
+		c.shards[i] = NewSizedCache(size, sizeFn)
+	}
+	return c
+}
+
+func (c *ShardedSizedCache[K, V]) shard(key K) *SizedCache[K, V] {
+	if len(c.shards) == 1 {
+		return c.shards[0]
+	}
+	return c.shards[c.hash(key)%uint64(len(c.shards))]
+}
+
+// Put inserts or replaces a value, evicting the least recently used entries
+// of the key's shard as needed.
+func (c *ShardedSizedCache[K, V]) Put(key K, value V) {
+	c.shard(key).Put(key, value)
+}
+
+// Get returns the entry with the key, if it exists.
+func (c *ShardedSizedCache[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).Get(key)
+}
+
+// Evict removes the specified entry from the cache.
+func (c *ShardedSizedCache[K, V]) Evict(key K) {
+	c.shard(key).Evict(key)
+}
+
+// Flush removes all entries from the cache.
+func (c *ShardedSizedCache[K, V]) Flush() {
+	for _, s := range c.shards {
+		s.Flush()
+	}
+}
+
+// Len returns the number of entries, summed over the shards.
+func (c *ShardedSizedCache[K, V]) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.Len()
+	}
+	return n
+}
+
+// PortionFilled returns the average of the fractions of each shard's size
+// that is used.
+func (c *ShardedSizedCache[K, V]) PortionFilled() float64 {
+	var sum float64
+	for _, s := range c.shards {
+		sum += s.PortionFilled()
+	}
+	return sum / float64(len(c.shards))
+}
+
+// Cap returns the maximum total size of the cache, summed over the shards.
+func (c *ShardedSizedCache[K, V]) Cap() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.Cap()
+	}
+	return n
+}
+
+// Size returns the total size of the cached entries, summed over the shards.
+func (c *ShardedSizedCache[K, V]) Size() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.Size()
+	}
+	return n
+}
+
+// Bytes returns the total size of the cached entries, like Size.
+func (c *ShardedSizedCache[K, V]) Bytes() int64 {
+	return int64(c.Size())
+}
+
+// MaxBytes returns the maximum total size of the cache, like Cap.
+func (c *ShardedSizedCache[K, V]) MaxBytes() int64 {
+	return int64(c.Cap())
+}
+
+// Bounded returns true, as the total size of the entries is at most Cap.
+func (*ShardedSizedCache[_, _]) Bounded() bool {
+	return true
+}
+
+// Stats returns the length, capacity in size units and fill of the cache,
+// summed over the shards. Like [SizedCache.Stats], the counters are
+// [cache.Untracked].
+func (c *ShardedSizedCache[K, V]) Stats() cache.Stats {
+	return cache.Stats{
+		Hits:          cache.Untracked,
+		Misses:        cache.Untracked,
+		Evictions:     cache.Untracked,
+		Len:           c.Len(),
+		Cap:           c.Cap(),
+		PortionFilled: c.PortionFilled(),
+	}
+}
+
+// All returns an iterator over the entries of the cache, shard by shard, each
+// from most to least recently used. Each shard is snapshotted when iteration
+// reaches it, so the loop body may safely call back into the cache.
+func (c *ShardedSizedCache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, s := range c.shards {
+			for key, value := range s.All() {
+				if !yield(key, value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// CheckInvariants checks the invariants of every shard, as
+// [SizedCache.CheckInvariants] does.
+func (c *ShardedSizedCache[K, V]) CheckInvariants() error {
+	for _, s := range c.shards {
+		if err := s.CheckInvariants(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package lru
+
+import (
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/cache"
+)
+
+func byteLen(_ int, v []byte) int { return len(v) }
+
+func TestShardedSizedCacheBudget(t *testing.T) {
+	require := require.New(t)
+
+	c := NewShardedSizedCache(4, 103, byteLen, func(k int) uint64 { return uint64(k) })
+	require.Equal(103, c.Cap())
+	require.Equal(int64(103), c.MaxBytes())
+	for i, want := range []int{26, 26, 26, 25} {
+		require.Equal(want, c.shards[i].Cap())
+	}
+
+	// An entry larger than a shard's share is not stored.
+	c.Put(0, make([]byte, 27))
+	require.Zero(c.Len())
+
+	c.Put(0, make([]byte, 20))
+	c.Put(4, make([]byte, 10))
+	// Key 4 shares shard 0 with key 0, which is evicted to make room.
+	require.Equal(int64(10), c.Bytes())
+	_, ok := c.Get(0)
+	require.False(ok)
+	c.Put(1, make([]byte, 26))
+	require.Equal(int64(36), c.Bytes())
+	require.Equal(2, c.Len())
+	require.InDelta((10.0/26+26.0/26)/4, c.PortionFilled(), 1e-9)
+
+	c.Flush()
+	require.Zero(c.Bytes())
+	require.Zero(c.Len())
+}
+
+func TestShardedSizedCacheAccounting(t *testing.T) {
+	require := require.New(t)
+
+	var c cache.Cacher[int, []byte] = NewShardedSizedCache[int, []byte](8, 1<<12, byteLen, nil)
+	sharded := c.(*ShardedSizedCache[int, []byte])
+	r := rand.New(rand.NewPCG(1, 1))
+	for range 10_000 {
+		key := r.IntN(256)
+		switch r.IntN(4) {
+		case 0:
+			c.Evict(key)
+		default:
+			c.Put(key, make([]byte, r.IntN(64)))
+		}
+	}
+
+	var total, n int
+	for _, v := range sharded.All() {
+		total += len(v)
+		n++
+	}
+	require.Equal(total, sharded.Size())
+	require.Equal(n, c.Len())
+	for _, s := range sharded.shards {
+		require.LessOrEqual(s.Size(), s.Cap())
+	}
+	require.NoError(sharded.CheckInvariants())
+}
+
+func TestShardedSizedCacheConcurrent(t *testing.T) {
+	c := NewShardedSizedCache[int, []byte](4, 1<<10, byteLen, nil)
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 1000 {
+				key := i*1000 + j%100
+				c.Put(key, make([]byte, j%32))
+				c.Get(key)
+				if j%10 == 0 {
+					c.Evict(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, c.CheckInvariants())
+	require.LessOrEqual(t, c.Size(), c.Cap())
+}
+
+func BenchmarkShardedSizedCacheContention(b *testing.B) {
+	value := make([]byte, 64)
+	caches := []struct {
+		name  string
+		cache cache.Cacher[int, []byte]
+	}{
+		{name: "sized", cache: NewSizedCache(1<<16, byteLen)},
+		{name: "sharded-16", cache: NewShardedSizedCache[int, []byte](16, 1<<16, byteLen, nil)},
+	}
+	for _, test := range caches {
+		b.Run(test.name, func(b *testing.B) {
+			c := test.cache
+			for i := range 1024 {
+				c.Put(i, value)
+			}
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				r := rand.New(rand.NewPCG(rand.Uint64(), 0))
+				for pb.Next() {
+					key := r.IntN(2048)
+					if _, ok := c.Get(key); !ok {
+						c.Put(key, value)
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/luxfi/cache"
+)
+
+// SharedBudget bounds the total size of several named caches. Each Namespace
+// is an independent key space, but all of them draw from one size budget and
+// are ordered by a single recency list, so when the budget is exhausted the
+// least recently used entry is evicted regardless of the namespace it belongs
+// to. A busy namespace can therefore grow into space left unused by idle ones.
+type SharedBudget[K comparable, V any] struct {
+	mu          sync.Mutex
+	maxSize     int
+	currentSize int
+	sizeFn      func(K, V) int
+	namespaces  map[string]*Namespace[K, V]
+	lru         *list.List // front is most recently used, across namespaces
+}
+
+// Namespace is a view of a SharedBudget holding its own set of keys.
+type Namespace[K comparable, V any] struct {
+	budget *SharedBudget[K, V]
+	name   string
+
+	// items and size are guarded by budget.mu.
+	items map[K]*list.Element
+	size  int
+}
+
+type budgetEntry[K comparable, V any] struct {
+	namespace *Namespace[K, V]
+	key       K
+	value     V
+	size      int
+}
+
+// NewSharedBudget creates a budget of maxSize shared by all of its
+// namespaces. A nil sizeFn gives every entry a size of 1.
+func NewSharedBudget[K comparable, V any](maxSize int, sizeFn func(K, V) int) *SharedBudget[K, V] {
+	if maxSize <= 0 {
+		maxSize = 1
+	}
+	if sizeFn == nil {
+		sizeFn = func(K, V) int { return 1 }
+	}
+	return &SharedBudget[K, V]{
+		maxSize:    maxSize,
+		sizeFn:     sizeFn,
+		namespaces: make(map[string]*Namespace[K, V]),
+		lru:        list.New(),
+	}
+}
+
+// Namespace returns the namespace with the given name, creating it if needed.
+// Calls with the same name return the same view.
+func (b *SharedBudget[K, V]) Namespace(name string) *Namespace[K, V] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n, ok := b.namespaces[name]
+	if !ok {
+		n = &Namespace[K, V]{
+			budget: b,
+			name:   name,
+			items:  make(map[K]*list.Element),
+		}
+		b.namespaces[name] = n
+	}
+	return n
+}
+
+// Cap returns the total size shared by the namespaces.
+func (b *SharedBudget[K, V]) Cap() int {
+	return b.maxSize
+}
+
+// Size returns the total size of the entries in all namespaces.
+func (b *SharedBudget[K, V]) Size() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentSize
+}
+
+func (b *SharedBudget[K, V]) removeElement(elem *list.Element) {
+	entry := elem.Value.(*budgetEntry[K, V])
+	b.currentSize -= entry.size
+	entry.namespace.size -= entry.size
+	delete(entry.namespace.items, entry.key)
+	b.lru.Remove(elem)
+}
+
+// Name returns the name of the namespace.
+func (n *Namespace[K, V]) Name() string {
+	return n.name
+}
+
+// Put inserts or replaces a value, evicting the least recently used entries
+// of any namespace until it fits. An entry larger than the whole budget is
+// not stored, and any previous value of the key is removed.
+func (n *Namespace[K, V]) Put(key K, value V) {
+	b := n.budget
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elem, ok := n.items[key]; ok {
+		b.removeElement(elem)
+	}
+
+	entrySize := b.sizeFn(key, value)
+	if entrySize > b.maxSize {
+		return
+	}
+	for b.currentSize > b.maxSize-entrySize {
+		back := b.lru.Back()
+		if back == nil {
+			break
+		}
+		b.removeElement(back)
+	}
+
+	n.items[key] = b.lru.PushFront(&budgetEntry[K, V]{
+		namespace: n,
+		key:       key,
+		value:     value,
+		size:      entrySize,
+	})
+	n.size += entrySize
+	b.currentSize += entrySize
+}
+
+// Get retrieves a value and marks it as most recently used.
+func (n *Namespace[K, V]) Get(key K) (V, bool) {
+	b := n.budget
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elem, ok := n.items[key]; ok {
+		b.lru.MoveToFront(elem)
+		return elem.Value.(*budgetEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Evict removes a key from the namespace.
+func (n *Namespace[K, V]) Evict(key K) {
+	b := n.budget
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elem, ok := n.items[key]; ok {
+		b.removeElement(elem)
+	}
+}
+
+// Flush removes all entries of the namespace, leaving other namespaces
+// untouched.
+func (n *Namespace[K, V]) Flush() {
+	b := n.budget
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, elem := range n.items {
+		b.removeElement(elem)
+	}
+}
+
+// Len returns the number of entries in the namespace.
+func (n *Namespace[K, V]) Len() int {
+	n.budget.mu.Lock()
+	defer n.budget.mu.Unlock()
+	return len(n.items)
+}
+
+// PortionFilled returns the ratio of the shared budget used by the namespace.
+func (n *Namespace[K, V]) PortionFilled() float64 {
+	n.budget.mu.Lock()
+	defer n.budget.mu.Unlock()
+	return float64(n.size) / float64(n.budget.maxSize)
+}
+
+// Cap returns the total size shared by the namespaces.
+func (n *Namespace[K, V]) Cap() int {
+	return n.budget.maxSize
+}
+
+// Size returns the total size of the entries in the namespace.
+func (n *Namespace[K, V]) Size() int {
+	n.budget.mu.Lock()
+	defer n.budget.mu.Unlock()
+	return n.size
+}
+
+// Bounded returns true, as every namespace draws from the shared budget.
+func (*Namespace[_, _]) Bounded() bool {
+	return true
+}
+
+var (
+	_ cache.Cacher[struct{}, struct{}] = (*Namespace[struct{}, struct{}])(nil)
+	_ cache.SizeReporter               = (*Namespace[struct{}, struct{}])(nil)
+	_ cache.BoundReporter              = (*Namespace[struct{}, struct{}])(nil)
+	_ cache.SizeReporter               = (*SharedBudget[struct{}, struct{}])(nil)
+)
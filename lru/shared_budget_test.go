@@ -0,0 +1,74 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedBudgetBorrowsFromIdleNamespace(t *testing.T) {
+	require := require.New(t)
+
+	budget := NewSharedBudget[int, int](10, nil)
+	headers := budget.Namespace("headers")
+	receipts := budget.Namespace("receipts")
+	require.Same(headers, budget.Namespace("headers"))
+
+	for i := 0; i < 5; i++ {
+		receipts.Put(i, i)
+	}
+
+	// headers grows past its even share while receipts is idle.
+	for i := 0; i < 8; i++ {
+		headers.Put(i, i)
+	}
+	require.Equal(8, headers.Len())
+	require.Equal(2, receipts.Len())
+	require.Equal(10, budget.Size())
+
+	// The coldest receipts were evicted first.
+	for i := 0; i < 3; i++ {
+		_, ok := receipts.Get(i)
+		require.False(ok)
+	}
+	for i := 3; i < 5; i++ {
+		v, ok := receipts.Get(i)
+		require.True(ok)
+		require.Equal(i, v)
+	}
+
+	// Recently used receipts now outlive the oldest headers.
+	headers.Put(8, 8)
+	headers.Put(9, 9)
+	_, ok := headers.Get(0)
+	require.False(ok)
+	require.Equal(2, receipts.Len())
+	require.InDelta(0.8, headers.PortionFilled(), 0.001)
+}
+
+func TestSharedBudgetNamespacesAreIsolated(t *testing.T) {
+	require := require.New(t)
+
+	budget := NewSharedBudget(100, func(_ string, v []byte) int { return len(v) })
+	a := budget.Namespace("a")
+	b := budget.Namespace("b")
+
+	a.Put("k", make([]byte, 10))
+	b.Put("k", make([]byte, 20))
+	require.Equal(10, a.Size())
+	require.Equal(20, b.Size())
+
+	a.Flush()
+	require.Zero(a.Len())
+	require.Equal(1, b.Len())
+	require.Equal(20, budget.Size())
+
+	// Oversized entries are dropped without evicting anything else.
+	b.Put("big", make([]byte, 101))
+	_, ok := b.Get("big")
+	require.False(ok)
+	require.Equal(20, budget.Size())
+
+	b.Evict("k")
+	require.Zero(budget.Size())
+}
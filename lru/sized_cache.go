@@ -4,26 +4,62 @@
 package lru
 
 import (
+	"container/heap"
 	"container/list"
+	"iter"
+	"slices"
 	"sync"
 
 	"github.com/luxfi/cache"
 )
 
 // SizedCache is an LRU cache bounded by total size rather than entry count.
+//
+// The size of an entry is computed by sizeFn when it is stored. Mutating a
+// stored value in place is discouraged, as the cache can't observe the change
+// and its accounting drifts; callers that do so must call Resync or ResyncAll
+// afterwards.
 type SizedCache[K comparable, V any] struct {
 	mu          sync.Mutex
 	maxSize     int
+	maxEntries  int // 0 means the number of entries is unbounded
 	currentSize int
 	sizeFn      func(K, V) int
 	items       map[K]*list.Element
 	lru         *list.List
+	thresholds  thresholds
+	sentinels   sentinelLane[K]
+
+	// gdsf, if non-nil, replaces LRU eviction with Greedy-Dual-Size-Frequency.
+	gdsf *gdsfPolicy[K, V]
 }
 
 type sizedEntry[K comparable, V any] struct {
 	key   K
 	value V
 	size  int
+
+	// The following fields are only maintained under GDSF eviction.
+	freq     int
+	cost     float64
+	priority float64
+	index    int
+}
+
+// EntrySize pairs a key with the size of its entry as reported by sizeFn.
+type EntrySize[K comparable] struct {
+	Key  K
+	Size int
+}
+
+// EntrySizeStats aggregates the sizes of the entries of a SizedCache. All
+// fields are zero for an empty cache.
+type EntrySizeStats struct {
+	Min   int
+	Max   int
+	Avg   int // Total / Count, rounded down
+	Total int
+	Count int
 }
 
 // NewSizedCache creates a size-bounded LRU cache.
@@ -42,77 +78,323 @@ func NewSizedCache[K comparable, V any](maxSize int, sizeFn func(K, V) int) *Siz
 	}
 }
 
+// NewSizedCacheWithLimits creates an LRU cache bounded by both total size and
+// number of entries. Entries are evicted until both bounds are satisfied. A
+// maxEntries of 0 leaves the number of entries unbounded.
+func NewSizedCacheWithLimits[K comparable, V any](maxSize, maxEntries int, sizeFn func(K, V) int) *SizedCache[K, V] {
+	c := NewSizedCache(maxSize, sizeFn)
+	c.maxEntries = max(maxEntries, 0)
+	return c
+}
+
 // Put inserts or replaces a value.
 func (c *SizedCache[K, V]) Put(key K, value V) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
+	c.put(key, value)
+}
 
+// PutReporting is like Put, but also reports whether it inserted a new entry,
+// rather than replacing an existing one, as a single atomic step. A value
+// larger than the cache is not stored, and reported as false.
+func (c *SizedCache[K, V]) PutReporting(key K, value V) (inserted bool) {
+	c.mu.Lock()
+	defer c.unlock()
+	return c.put(key, value)
+}
+
+// put stores value and reports whether the key was missing. Must be called
+// with the lock held.
+func (c *SizedCache[K, V]) put(key K, value V) (inserted bool) {
 	entrySize := c.sizeFn(key, value)
 	if entrySize > c.maxSize {
+		// The value replaces whatever the key held, so its sentinel goes
+		// with the entries. Other keys' sentinels stay, as they don't take
+		// room from entries.
 		c.flushLocked()
-		return
+		c.sentinels.remove(key)
+		return false
 	}
 
-	if elem, ok := c.items[key]; ok {
-		oldEntry := elem.Value.(*sizedEntry[K, V])
-		c.currentSize -= oldEntry.size
-		c.lru.Remove(elem)
-		delete(c.items, key)
+	freq := 0
+	elem, existed := c.items[key]
+	if existed {
+		freq = c.removeElement(elem).freq
 	}
 
-	for c.currentSize > c.maxSize-entrySize {
-		back := c.lru.Back()
-		if back == nil {
+	for c.currentSize > c.maxSize-entrySize || (c.maxEntries > 0 && len(c.items) >= c.maxEntries) {
+		if !c.evictOne() {
 			break
 		}
-		oldEntry := back.Value.(*sizedEntry[K, V])
-		c.currentSize -= oldEntry.size
-		delete(c.items, oldEntry.key)
-		c.lru.Remove(back)
 	}
 
-	e := &sizedEntry[K, V]{key: key, value: value, size: entrySize}
+	c.sentinels.remove(key)
+	e := &sizedEntry[K, V]{key: key, value: value, size: entrySize, freq: freq}
 	c.items[key] = c.lru.PushFront(e)
 	c.currentSize += entrySize
+	if c.gdsf != nil {
+		c.gdsf.add(key, value, e)
+	}
+	return !existed
 }
 
 // Get retrieves a value and marks it as most recently used.
 func (c *SizedCache[K, V]) Get(key K) (V, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	return c.get(key)
+}
 
+// get is Get, with the lock held by the caller.
+func (c *SizedCache[K, V]) get(key K) (V, bool) {
 	if elem, ok := c.items[key]; ok {
 		c.lru.MoveToFront(elem)
-		return elem.Value.(*sizedEntry[K, V]).value, true
+		entry := elem.Value.(*sizedEntry[K, V])
+		if c.gdsf != nil {
+			c.gdsf.touch(entry)
+		}
+		return entry.value, true
 	}
 	var zero V
 	return zero, false
 }
 
-// Evict removes a key from the cache.
-func (c *SizedCache[K, V]) Evict(key K) {
+// GetOrDefault returns the value of key, or def if the key is missing. A hit
+// marks the entry as most recently used, like Get.
+func (c *SizedCache[K, V]) GetOrDefault(key K, def V) V {
+	if value, ok := c.Get(key); ok {
+		return value
+	}
+	return def
+}
+
+// GetOrZero returns the value of key, or the zero value if the key is missing.
+func (c *SizedCache[K, V]) GetOrZero(key K) V {
+	value, _ := c.Get(key)
+	return value
+}
+
+// Touch marks the entry with the key as most recently used without reading
+// its value, returning whether the key exists.
+func (c *SizedCache[K, V]) Touch(key K) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	elem, ok := c.items[key]
+	if ok {
+		c.lru.MoveToFront(elem)
+		if c.gdsf != nil {
+			c.gdsf.touch(elem.Value.(*sizedEntry[K, V]))
+		}
+	}
+	return ok
+}
+
+// Evict removes a key, or its sentinel, from the cache.
+func (c *SizedCache[K, V]) Evict(key K) {
+	c.mu.Lock()
+	defer c.unlock()
+
 	if elem, ok := c.items[key]; ok {
-		entry := elem.Value.(*sizedEntry[K, V])
-		c.currentSize -= entry.size
-		delete(c.items, key)
-		c.lru.Remove(elem)
+		c.removeElement(elem)
 	}
+	c.sentinels.remove(key)
 }
 
-// Flush removes all entries.
-func (c *SizedCache[K, V]) Flush() {
+// DelGet removes key and returns its value, reporting whether it was present,
+// under a single lock acquisition. A sentinel of the key is removed too, but
+// reported as missing.
+func (c *SizedCache[K, V]) DelGet(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.sentinels.remove(key)
+	if elem, ok := c.items[key]; ok {
+		return c.removeElement(elem).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// EvictMany removes every listed key under a single lock acquisition and
+// returns the number of entries removed. Missing and repeated keys are
+// ignored. Sentinels of the keys are removed too, and counted.
+func (c *SizedCache[K, V]) EvictMany(keys []K) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	removed := 0
+	for _, key := range keys {
+		if elem, ok := c.items[key]; ok {
+			c.removeElement(elem)
+			removed++
+		} else if c.sentinels.remove(key) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// EvictFunc removes every entry for which pred returns true and returns the
+// number of entries removed, holding the lock for the whole pass. pred must not
+// call back into the cache.
+func (c *SizedCache[K, V]) EvictFunc(pred func(K, V) bool) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	removed := 0
+	for e := c.lru.Front(); e != nil; {
+		next := e.Next()
+		if entry := e.Value.(*sizedEntry[K, V]); pred(entry.key, entry.value) {
+			c.removeElement(e)
+			removed++
+		}
+		e = next
+	}
+	return removed
+}
+
+// Resync recomputes the size of the entry with the key using sizeFn,
+// returning whether the key exists. If the entry grew beyond the remaining
+// budget, entries are evicted until the cache fits again, which may include
+// the resynced entry itself. Resync does not affect the recency order.
+func (c *SizedCache[K, V]) Resync(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.resyncElement(elem)
+	c.evictOverflow()
+	return true
+}
+
+// ResyncAll recomputes the size of every entry, as Resync does, holding the
+// lock for the whole pass.
+func (c *SizedCache[K, V]) ResyncAll() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for e := c.lru.Front(); e != nil; e = e.Next() {
+		c.resyncElement(e)
+	}
+	c.evictOverflow()
+}
+
+func (c *SizedCache[K, V]) resyncElement(elem *list.Element) {
+	entry := elem.Value.(*sizedEntry[K, V])
+	size := c.sizeFn(entry.key, entry.value)
+	c.currentSize += size - entry.size
+	entry.size = size
+	if c.gdsf != nil {
+		c.gdsf.resize(entry)
+	}
+}
+
+// Reserve evicts entries, in eviction order, until at least bytes of the
+// capacity are free and, if the cache limits its number of entries, there is
+// room for entries more. It returns the number of entries evicted. A
+// reservation larger than the capacity is clamped to it. Calling Reserve with
+// the total size and number of entries of a batch before Putting it does the
+// eviction work up front, so that the Puts themselves evict nothing unless
+// other writers fill the room first.
+func (c *SizedCache[K, V]) Reserve(bytes, entries int) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	bytes = min(bytes, c.maxSize)
+	maxLen := len(c.items)
+	if c.maxEntries > 0 {
+		maxLen = c.maxEntries - min(max(entries, 0), c.maxEntries)
+	}
+	evicted := 0
+	for (c.currentSize > c.maxSize-bytes || len(c.items) > maxLen) && c.evictOne() {
+		evicted++
+	}
+	return evicted
+}
+
+// evictOverflow evicts entries until the total size is within maxSize.
+func (c *SizedCache[K, V]) evictOverflow() {
+	for c.currentSize > c.maxSize {
+		if !c.evictOne() {
+			return
+		}
+	}
+}
+
+// OnThreshold registers fn to be called when the fill ratio of the cache, as
+// reported by PortionFilled, reaches ratio from below. It behaves like
+// [Cache.OnThreshold].
+func (c *SizedCache[K, V]) OnThreshold(ratio float64, fn func(current float64)) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.thresholds = append(c.thresholds, &threshold{
+		ratio: ratio,
+		fn:    fn,
+		armed: true,
+	})
+}
+
+// unlock releases the lock and then invokes the callbacks of the thresholds
+// crossed while it was held.
+func (c *SizedCache[K, V]) unlock() {
+	var calls []thresholdCall
+	if len(c.thresholds) > 0 {
+		calls = c.thresholds.check(float64(c.currentSize)/float64(c.maxSize), nil)
+	}
+	c.mu.Unlock()
+	invokeThresholds(calls)
+}
+
+// Flush removes all entries and sentinels.
+func (c *SizedCache[K, V]) Flush() {
+	c.mu.Lock()
+	defer c.unlock()
 	c.flushLocked()
+	c.sentinels.reset()
 }
 
 func (c *SizedCache[K, V]) flushLocked() {
 	c.items = make(map[K]*list.Element)
 	c.lru.Init()
 	c.currentSize = 0
+	if c.gdsf != nil {
+		c.gdsf.reset()
+	}
+}
+
+// evictOne removes the entry chosen by the eviction policy, returning false if
+// the cache is empty.
+func (c *SizedCache[K, V]) evictOne() bool {
+	var victim *list.Element
+	if c.gdsf != nil {
+		entry, ok := c.gdsf.victim()
+		if !ok {
+			return false
+		}
+		victim = c.items[entry.key]
+	} else {
+		victim = c.lru.Back()
+	}
+	if victim == nil {
+		return false
+	}
+	c.removeElement(victim)
+	return true
+}
+
+func (c *SizedCache[K, V]) removeElement(elem *list.Element) *sizedEntry[K, V] {
+	entry := elem.Value.(*sizedEntry[K, V])
+	c.currentSize -= entry.size
+	delete(c.items, entry.key)
+	c.lru.Remove(elem)
+	if c.gdsf != nil {
+		c.gdsf.remove(entry)
+	}
+	return entry
 }
 
 // Len returns number of entries.
@@ -132,4 +414,146 @@ func (c *SizedCache[K, V]) PortionFilled() float64 {
 	return float64(c.currentSize) / float64(c.maxSize)
 }
 
-var _ cache.Cacher[struct{}, struct{}] = (*SizedCache[struct{}, struct{}])(nil)
+// Bounded returns true, as the total size of the entries is at most Cap.
+func (*SizedCache[_, _]) Bounded() bool {
+	return true
+}
+
+// Stats returns the length, capacity in size units and fill of the cache.
+// SizedCache keeps no hit, miss or eviction counters, so those fields are
+// [cache.Untracked].
+func (c *SizedCache[K, V]) Stats() cache.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return cache.Stats{
+		Hits:          cache.Untracked,
+		Misses:        cache.Untracked,
+		Evictions:     cache.Untracked,
+		Len:           len(c.items),
+		Cap:           c.maxSize,
+		PortionFilled: float64(c.currentSize) / float64(c.maxSize),
+	}
+}
+
+// All returns an iterator over the entries of the cache, from most to least
+// recently used. The entries are snapshotted when iteration starts, so the
+// loop body may safely call back into the cache; iteration does not affect
+// the recency order.
+func (c *SizedCache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		c.mu.Lock()
+		entries := make([]sizedEntry[K, V], 0, len(c.items))
+		for e := c.lru.Front(); e != nil; e = e.Next() {
+			entries = append(entries, *e.Value.(*sizedEntry[K, V]))
+		}
+		c.mu.Unlock()
+
+		for _, entry := range entries {
+			if !yield(entry.key, entry.value) {
+				return
+			}
+		}
+	}
+}
+
+// LargestEntries returns up to n entries with the largest stored size, sorted
+// by size in descending order. Ties are broken arbitrarily. The cache is
+// locked once, and selection keeps only n candidates at a time rather than
+// sorting every entry.
+func (c *SizedCache[K, V]) LargestEntries(n int) []EntrySize[K] {
+	if n <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	h := make(entrySizeHeap[K], 0, min(n, len(c.items)))
+	for e := c.lru.Front(); e != nil; e = e.Next() {
+		entry := e.Value.(*sizedEntry[K, V])
+		switch {
+		case len(h) < n:
+			heap.Push(&h, EntrySize[K]{Key: entry.key, Size: entry.size})
+		case entry.size > h[0].Size:
+			h[0] = EntrySize[K]{Key: entry.key, Size: entry.size}
+			heap.Fix(&h, 0)
+		}
+	}
+	c.mu.Unlock()
+
+	slices.SortFunc(h, func(a, b EntrySize[K]) int {
+		return b.Size - a.Size
+	})
+	return h
+}
+
+// entrySizeHeap is a min-heap of entries ordered by size.
+type entrySizeHeap[K comparable] []EntrySize[K]
+
+func (h entrySizeHeap[_]) Len() int           { return len(h) }
+func (h entrySizeHeap[_]) Less(i, j int) bool { return h[i].Size < h[j].Size }
+func (h entrySizeHeap[_]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *entrySizeHeap[K]) Push(x any) {
+	*h = append(*h, x.(EntrySize[K]))
+}
+
+func (h *entrySizeHeap[K]) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// SizeStats returns the distribution of entry sizes, computed in a single pass
+// under the lock.
+func (c *SizedCache[K, V]) SizeStats() EntrySizeStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var stats EntrySizeStats
+	for e := c.lru.Front(); e != nil; e = e.Next() {
+		size := e.Value.(*sizedEntry[K, V]).size
+		if stats.Count == 0 || size < stats.Min {
+			stats.Min = size
+		}
+		stats.Max = max(stats.Max, size)
+		stats.Total += size
+		stats.Count++
+	}
+	if stats.Count > 0 {
+		stats.Avg = stats.Total / stats.Count
+	}
+	return stats
+}
+
+// Cap returns the maximum total size of the cache.
+func (c *SizedCache[K, V]) Cap() int {
+	return c.maxSize
+}
+
+// Size returns the total size of the cached entries.
+func (c *SizedCache[K, V]) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.currentSize
+}
+
+// Bytes returns the total size of the cached entries, like Size. It implements
+// [cache.ByteSized], assuming sizeFn reports sizes in bytes.
+func (c *SizedCache[K, V]) Bytes() int64 {
+	return int64(c.Size())
+}
+
+// MaxBytes returns the maximum total size of the cache, like Cap.
+func (c *SizedCache[K, V]) MaxBytes() int64 {
+	return int64(c.Cap())
+}
+
+var (
+	_ cache.Cacher[struct{}, struct{}]   = (*SizedCache[struct{}, struct{}])(nil)
+	_ cache.Iterable[struct{}, struct{}] = (*SizedCache[struct{}, struct{}])(nil)
+	_ cache.SizeReporter                 = (*SizedCache[struct{}, struct{}])(nil)
+	_ cache.ByteSized                    = (*SizedCache[struct{}, struct{}])(nil)
+	_ cache.StatsReporter                = (*SizedCache[struct{}, struct{}])(nil)
+	_ cache.BoundReporter                = (*SizedCache[struct{}, struct{}])(nil)
+)
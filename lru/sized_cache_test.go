@@ -0,0 +1,334 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/cache"
+)
+
+func TestSizedCacheLargestEntries(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCache[string, int](100, func(_ string, v int) int { return v })
+	cache.Put("a", 5)
+	cache.Put("b", 20)
+	cache.Put("c", 1)
+	cache.Put("d", 30)
+	cache.Put("e", 10)
+
+	require.Equal([]EntrySize[string]{
+		{Key: "d", Size: 30},
+		{Key: "b", Size: 20},
+		{Key: "e", Size: 10},
+	}, cache.LargestEntries(3))
+
+	require.Len(cache.LargestEntries(10), 5)
+	require.Empty(cache.LargestEntries(0))
+}
+
+func TestSizedCacheEvictFunc(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCache[int, int](100, func(_ int, v int) int { return v })
+	for i := 1; i <= 5; i++ {
+		cache.Put(i, i)
+	}
+
+	removed := cache.EvictFunc(func(_ int, v int) bool {
+		return v > 3
+	})
+	require.Equal(2, removed)
+	require.Equal(3, cache.Len())
+	require.InDelta(0.06, cache.PortionFilled(), 1e-9)
+	for i := 1; i <= 3; i++ {
+		_, ok := cache.Get(i)
+		require.True(ok)
+	}
+}
+
+func TestSizedCacheAll(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCache[int, int](100, nil)
+	cache.Put(1, 10)
+	cache.Put(2, 20)
+	cache.Put(3, 30)
+
+	var keys []int
+	for k, v := range cache.All() {
+		require.Equal(k*10, v)
+		keys = append(keys, k)
+		if k == 2 {
+			break
+		}
+	}
+	require.Equal([]int{3, 2}, keys)
+}
+
+func TestSizedCacheTouch(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCache[int, int](2, nil)
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	require.True(cache.Touch(1))
+	require.False(cache.Touch(3))
+
+	cache.Put(3, 3)
+	_, ok := cache.Get(1)
+	require.True(ok)
+	_, ok = cache.Get(2)
+	require.False(ok)
+}
+
+func TestSizedCacheWithLimits(t *testing.T) {
+	sizeFn := func(_ int, v int) int { return v }
+
+	t.Run("entries bound", func(t *testing.T) {
+		require := require.New(t)
+
+		cache := NewSizedCacheWithLimits[int, int](100, 2, sizeFn)
+		cache.Put(1, 1)
+		cache.Put(2, 1)
+		cache.Put(3, 1)
+		require.Equal(2, cache.Len())
+		_, ok := cache.Get(1)
+		require.False(ok)
+
+		// Replacing an existing key doesn't evict.
+		cache.Put(3, 2)
+		require.Equal(2, cache.Len())
+	})
+
+	t.Run("size bound", func(t *testing.T) {
+		require := require.New(t)
+
+		cache := NewSizedCacheWithLimits[int, int](10, 5, sizeFn)
+		cache.Put(1, 6)
+		cache.Put(2, 6)
+		require.Equal(1, cache.Len())
+		_, ok := cache.Get(1)
+		require.False(ok)
+	})
+
+	t.Run("unbounded entries", func(t *testing.T) {
+		require := require.New(t)
+
+		cache := NewSizedCacheWithLimits[int, int](100, 0, sizeFn)
+		for i := 0; i < 50; i++ {
+			cache.Put(i, 1)
+		}
+		require.Equal(50, cache.Len())
+	})
+}
+
+func TestSizedCacheSizeReporter(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCache[int, int](10, func(_ int, v int) int { return v })
+	cache.Put(1, 3)
+	cache.Put(2, 4)
+	require.Equal(10, cache.Cap())
+	require.Equal(7, cache.Size())
+}
+
+func TestSizedCacheByteSized(t *testing.T) {
+	require := require.New(t)
+
+	var sized cache.ByteSized = NewSizedCache(10, func(_ int, v []byte) int { return len(v) })
+	require.Equal(int64(10), sized.MaxBytes())
+	require.Zero(sized.Bytes())
+
+	c := sized.(*SizedCache[int, []byte])
+	c.Put(1, []byte("abc"))
+	c.Put(2, []byte("defg"))
+	require.Equal(int64(7), sized.Bytes())
+	c.Put(1, []byte("a"))
+	require.Equal(int64(5), sized.Bytes())
+	c.Put(3, []byte("hijklmn"))
+	require.Equal(int64(8), sized.Bytes())
+	c.Evict(3)
+	require.Equal(int64(1), sized.Bytes())
+}
+
+func TestSizedCacheStats(t *testing.T) {
+	require := require.New(t)
+
+	c := NewSizedCache[int, int](10, func(_ int, v int) int { return v })
+	c.Put(1, 3)
+	c.Put(2, 4)
+	require.Equal(cache.Stats{
+		Hits:          cache.Untracked,
+		Misses:        cache.Untracked,
+		Evictions:     cache.Untracked,
+		Len:           2,
+		Cap:           10,
+		PortionFilled: 0.7,
+	}, c.Stats())
+}
+
+func TestSizedCacheDelGet(t *testing.T) {
+	require := require.New(t)
+
+	c := NewSizedCache[int, int](10, func(_ int, v int) int { return v })
+	c.Put(1, 3)
+
+	v, ok := c.DelGet(1)
+	require.True(ok)
+	require.Equal(3, v)
+	require.Zero(c.Size())
+
+	_, ok = c.DelGet(1)
+	require.False(ok)
+}
+
+func TestSizedCacheResync(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCache(100, func(_ int, v *[]byte) int { return len(*v) })
+	a := make([]byte, 10)
+	b := make([]byte, 20)
+	cache.Put(1, &a)
+	cache.Put(2, &b)
+	require.Equal(30, cache.Size())
+	require.False(cache.Resync(3))
+
+	// Mutating a stored slice in place isn't observed until resynced.
+	a = append(a, make([]byte, 30)...)
+	require.Equal(30, cache.Size())
+	require.True(cache.Resync(1))
+	require.Equal(60, cache.Size())
+
+	// Growing past the budget evicts the least recently used entries until
+	// the cache fits again.
+	b = b[:0]
+	a = append(a, make([]byte, 70)...)
+	cache.ResyncAll()
+	require.Zero(cache.Size())
+	require.Equal(1, cache.Len())
+	_, ok := cache.Get(1)
+	require.False(ok)
+}
+
+func TestSizedCacheEvictMany(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCache(100, func(_, v int) int { return v })
+	for i := 1; i <= 4; i++ {
+		cache.Put(i, i)
+	}
+	require.Equal(2, cache.EvictMany([]int{1, 3, 3, 7}))
+	require.Equal(2, cache.Len())
+	require.Equal(6, cache.Size())
+}
+
+func TestSizedCacheGetOrDefault(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCache[int, int](2, nil)
+	cache.Put(1, 10)
+	cache.Put(2, 20)
+	require.Equal(10, cache.GetOrDefault(1, -1))
+	require.Equal(-1, cache.GetOrDefault(3, -1))
+	require.Zero(cache.GetOrZero(3))
+
+	// The hit promoted 1, so 2 is evicted next.
+	cache.Put(3, 30)
+	require.Equal(10, cache.GetOrZero(1))
+	require.Zero(cache.GetOrZero(2))
+}
+
+func TestSizedCacheSizeStats(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCache(100, func(_, v int) int { return v })
+	require.Equal(EntrySizeStats{}, cache.SizeStats())
+
+	for i, size := range []int{5, 20, 3, 12} {
+		cache.Put(i, size)
+	}
+	require.Equal(EntrySizeStats{
+		Min:   3,
+		Max:   20,
+		Avg:   10,
+		Total: 40,
+		Count: 4,
+	}, cache.SizeStats())
+}
+
+func TestSizedCacheReserve(t *testing.T) {
+	require := require.New(t)
+
+	c := NewSizedCache(10, func(_ int, v int) int { return v })
+	for i := range 5 {
+		c.Put(i, 2)
+	}
+	c.Get(0)
+	has := func(k int) bool {
+		_, ok := c.Get(k)
+		return ok
+	}
+
+	// 1 and 2 are the least recently used entries.
+	require.Equal(2, c.Reserve(3, 2))
+	require.Equal(6, c.Size())
+	require.False(has(1))
+	require.False(has(2))
+
+	// The batch fits in the reserved room, so nothing else is evicted.
+	c.Put(10, 1)
+	c.Put(11, 3)
+	require.Equal(5, c.Len())
+	for _, k := range []int{0, 3, 4, 10, 11} {
+		require.True(has(k))
+	}
+
+	require.Zero(c.Reserve(0, 0))
+	require.Equal(5, c.Reserve(100, 0))
+	require.Zero(c.Len())
+}
+
+func TestSizedCacheReserveEntries(t *testing.T) {
+	require := require.New(t)
+
+	c := NewSizedCacheWithLimits(100, 4, func(_ int, v int) int { return v })
+	for i := range 4 {
+		c.Put(i, 1)
+	}
+	keys := func() []int {
+		var keys []int
+		for k := range c.All() {
+			keys = append(keys, k)
+		}
+		return keys
+	}
+
+	// There is room for the bytes already, but not for the entries.
+	require.Equal(2, c.Reserve(2, 2))
+	require.Equal([]int{3, 2}, keys())
+
+	// The batch fits in the reserved room, so nothing else is evicted.
+	c.Put(10, 1)
+	c.Put(11, 1)
+	require.Equal([]int{11, 10, 3, 2}, keys())
+
+	require.Equal(4, c.Reserve(0, 100))
+	require.Zero(c.Len())
+}
+
+func TestSizedCachePutReporting(t *testing.T) {
+	require := require.New(t)
+
+	c := NewSizedCache[int, int](10, func(_ int, v int) int { return v })
+	require.True(c.PutReporting(1, 2))
+	require.False(c.PutReporting(1, 3))
+	v, ok := c.Get(1)
+	require.True(ok)
+	require.Equal(3, v)
+
+	// Too large to store, so nothing was inserted.
+	require.False(c.PutReporting(2, 11))
+	require.Zero(c.Len())
+}
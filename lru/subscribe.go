@@ -0,0 +1,87 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import "slices"
+
+// EventType identifies the mutation described by an Event.
+type EventType int
+
+const (
+	// EventPut reports that Key was stored with Value, either as a new entry
+	// or as an update.
+	EventPut EventType = iota
+	// EventEvict reports that the entry with Key and Value was removed,
+	// whether to make room, explicitly or because it expired.
+	EventEvict
+	// EventFlush reports that every entry was removed at once. Key and Value
+	// are the zero values.
+	EventFlush
+)
+
+// Event describes a mutation of a Cache.
+type Event[K comparable, V any] struct {
+	Type  EventType
+	Key   K
+	Value V
+}
+
+type subscriber[K comparable, V any] struct {
+	fn func(Event[K, V])
+}
+
+// Subscribe registers fn to be called with every mutation of the cache and
+// returns a function that unregisters it. Events are delivered after the lock
+// has been released, on the goroutine that made the mutation, in the order
+// the mutations happened, and to subscribers in registration order. fn may
+// call back into the cache.
+//
+// Unsubscribing is idempotent. Events of an operation already in progress may
+// still be delivered after unsubscribing.
+func (c *Cache[K, V]) Subscribe(fn func(Event[K, V])) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := &subscriber[K, V]{fn: fn}
+	// The slice is replaced rather than modified so that unlock can deliver
+	// events to a snapshot without holding the lock.
+	c.subscribers = append(slices.Clip(c.subscribers), s)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.subscribers = slices.DeleteFunc(slices.Clone(c.subscribers), func(other *subscriber[K, V]) bool {
+			return other == s
+		})
+	}
+}
+
+// emit queues an event for delivery by unlock. It does nothing without
+// subscribers.
+func (c *Cache[K, V]) emit(typ EventType, key K, value V) {
+	if len(c.subscribers) == 0 {
+		return
+	}
+	c.events = append(c.events, Event[K, V]{
+		Type:  typ,
+		Key:   key,
+		Value: value,
+	})
+}
+
+// emitFlush queues an EventFlush for delivery by unlock.
+func (c *Cache[K, V]) emitFlush() {
+	var (
+		key   K
+		value V
+	)
+	c.emit(EventFlush, key, value)
+}
+
+func deliver[K comparable, V any](subscribers []*subscriber[K, V], events []Event[K, V]) {
+	for _, event := range events {
+		for _, s := range subscribers {
+			s.fn(event)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribe(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[int, int](2)
+	var (
+		order  []string
+		events []Event[int, int]
+	)
+	unsubscribe := cache.Subscribe(func(e Event[int, int]) {
+		order = append(order, "first")
+		events = append(events, e)
+		// Subscribers run without the lock held.
+		_ = cache.Contains(e.Key)
+	})
+	cache.Subscribe(func(Event[int, int]) {
+		order = append(order, "second")
+	})
+
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(1, 10)
+	cache.Put(3, 3) // evicts 2
+	cache.Delete(3)
+	cache.Flush()
+	require.Equal([]Event[int, int]{
+		{Type: EventPut, Key: 1, Value: 1},
+		{Type: EventPut, Key: 2, Value: 2},
+		{Type: EventPut, Key: 1, Value: 10},
+		{Type: EventEvict, Key: 2, Value: 2},
+		{Type: EventPut, Key: 3, Value: 3},
+		{Type: EventEvict, Key: 3, Value: 3},
+		{Type: EventFlush},
+	}, events)
+	require.Len(order, 2*len(events))
+	for i := 0; i < len(order); i += 2 {
+		require.Equal([]string{"first", "second"}, order[i:i+2])
+	}
+
+	unsubscribe()
+	unsubscribe()
+	cache.Put(4, 4)
+	require.Len(events, 7)
+	require.Equal("second", order[len(order)-1])
+}
+
+func TestSubscribeAllocs(t *testing.T) {
+	cache := NewCache[int, int](2)
+	cache.Put(1, 1)
+
+	// Mutations don't queue events without subscribers.
+	allocs := testing.AllocsPerRun(100, func() {
+		cache.Put(1, 1)
+	})
+	require.Zero(t, allocs)
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+const (
+	// thrashBuckets is the number of buckets of the window ThrashRatio is
+	// measured over, each counting thrashBucketPuts puts. The window slides
+	// by a whole bucket at a time.
+	thrashBuckets    = 8
+	thrashBucketPuts = 128
+
+	// ThrashWindow is the largest number of puts ThrashRatio is measured
+	// over. Except while the cache is young, the window holds between
+	// ThrashWindow-128 and ThrashWindow of the most recent puts.
+	ThrashWindow = thrashBuckets * thrashBucketPuts
+)
+
+// thrashBucket counts the puts of a slice of the window and the evictions
+// they caused.
+type thrashBucket struct {
+	puts      uint32
+	evictions uint32
+}
+
+// thrashWindow is a ring of buckets counting the most recent puts. puts and
+// evictions are the totals of the buckets.
+type thrashWindow struct {
+	buckets   [thrashBuckets]thrashBucket
+	current   int
+	puts      uint32
+	evictions uint32
+}
+
+// record counts a put, which caused an eviction if evicted is set.
+func (w *thrashWindow) record(evicted bool) {
+	b := &w.buckets[w.current]
+	if b.puts == thrashBucketPuts {
+		w.current = (w.current + 1) % thrashBuckets
+		b = &w.buckets[w.current]
+		w.puts -= b.puts
+		w.evictions -= b.evictions
+		*b = thrashBucket{}
+	}
+	b.puts++
+	w.puts++
+	if evicted {
+		b.evictions++
+		w.evictions++
+	}
+}
+
+func (w *thrashWindow) ratio() float64 {
+	if w.puts == 0 {
+		return 0
+	}
+	return float64(w.evictions) / float64(w.puts)
+}
+
+// ThrashRatio returns the fraction of the recent puts that evicted an entry to
+// make room, measured over the last ThrashWindow puts or so. A cache whose
+// working set fits has a ratio near 0 once warm, while a ratio near 1 means
+// that nearly every put pushes out another entry: the cache is too small for
+// its workload, and entries are likely evicted before they are read again.
+// Every call storing a value counts as a put, including updates of existing
+// keys, which never evict.
+func (c *Cache[K, V]) ThrashRatio() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.thrash.ratio()
+}
+
+// OnThrash registers fn to be called when the ThrashRatio of the cache reaches
+// ratio from below, signaling that the cache is undersized. The ratio is only
+// checked once the window holds at least 128 puts, so that the first puts
+// into a full cache don't fire it. Like the thresholds of OnThreshold, it
+// only fires again after the ratio has dropped at least 0.05 below ratio.
+//
+// fn is invoked with the ratio observed, after the lock was released, on the
+// goroutine whose put crossed the threshold.
+func (c *Cache[K, V]) OnThrash(ratio float64, fn func(current float64)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.thrashThresholds = append(c.thrashThresholds, &threshold{
+		ratio: ratio,
+		fn:    fn,
+		armed: true,
+	})
+}
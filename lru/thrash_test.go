@@ -0,0 +1,73 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrashRatio(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCache[int, int](100)
+	var fired []float64
+	c.OnThrash(0.5, func(current float64) { fired = append(fired, current) })
+	require.Zero(c.ThrashRatio())
+
+	// A working set that fits doesn't thrash.
+	for i := range 5 * ThrashWindow {
+		c.Put(i%100, i)
+	}
+	require.Zero(c.ThrashRatio())
+	require.Empty(fired)
+
+	// Cycling through twice the capacity evicts on every put.
+	for i := range ThrashWindow {
+		c.Put(i%200, i)
+	}
+	require.Greater(c.ThrashRatio(), 0.8)
+	require.Len(fired, 1)
+	require.GreaterOrEqual(fired[0], 0.5)
+
+	// Once resized to fit, the ratio drops as the window slides.
+	c.Resize(200)
+	for i := range ThrashWindow {
+		c.Put(i%200, i)
+	}
+	require.Less(c.ThrashRatio(), 0.2)
+
+	// The callback fires again after the ratio dropped.
+	for i := range ThrashWindow {
+		c.Put(1000+i, i)
+	}
+	require.Len(fired, 2)
+}
+
+func TestThrashRatioCountsUpdates(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCache[int, int](2)
+	for i := range 100 {
+		c.Put(i, i)
+		c.Put(i, i+1)
+	}
+	require.InDelta(0.49, c.ThrashRatio(), 0.01)
+}
+
+func TestThrashWindowSlides(t *testing.T) {
+	require := require.New(t)
+
+	var w thrashWindow
+	for range ThrashWindow {
+		w.record(true)
+	}
+	require.Equal(1.0, w.ratio())
+	for range ThrashWindow - thrashBucketPuts {
+		w.record(false)
+	}
+	require.Equal(uint32(ThrashWindow), w.puts)
+	require.Equal(uint32(thrashBucketPuts), w.evictions)
+	w.record(false)
+	require.Equal(uint32(ThrashWindow-thrashBucketPuts+1), w.puts)
+	require.Zero(w.evictions)
+}
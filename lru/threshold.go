@@ -0,0 +1,45 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+// thresholdHysteresis is how far below its ratio the fill of a cache must drop
+// before a threshold that fired can fire again, so that a cache hovering
+// around the ratio doesn't fire on every write.
+const thresholdHysteresis = 0.05
+
+// threshold is a fill ratio registered with OnThreshold.
+type threshold struct {
+	ratio float64
+	fn    func(current float64)
+	armed bool
+}
+
+// thresholdCall is a threshold callback to invoke once the lock is released.
+type thresholdCall struct {
+	fn      func(current float64)
+	current float64
+}
+
+type thresholds []*threshold
+
+// check updates every threshold with the current fill ratio and appends the
+// callbacks of those that crossed upward to calls.
+func (ts thresholds) check(current float64, calls []thresholdCall) []thresholdCall {
+	for _, t := range ts {
+		switch {
+		case t.armed && current >= t.ratio:
+			t.armed = false
+			calls = append(calls, thresholdCall{fn: t.fn, current: current})
+		case !t.armed && current <= t.ratio-thresholdHysteresis:
+			t.armed = true
+		}
+	}
+	return calls
+}
+
+func invokeThresholds(calls []thresholdCall) {
+	for _, call := range calls {
+		call.fn(call.current)
+	}
+}
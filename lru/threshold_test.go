@@ -0,0 +1,56 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheOnThreshold(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewCache[int, int](10)
+	var fired []float64
+	cache.OnThreshold(0.9, func(current float64) {
+		// Callbacks run without the lock held.
+		_ = cache.Len()
+		fired = append(fired, current)
+	})
+
+	for i := 0; i < 20; i++ {
+		cache.Put(i, i)
+	}
+	require.Equal([]float64{0.9}, fired)
+
+	// Hovering around the threshold doesn't fire again.
+	cache.Delete(19)
+	cache.Put(19, 19)
+	require.Len(fired, 1)
+
+	// Dropping well below the threshold re-arms it.
+	cache.EvictMany([]int{10, 11, 12})
+	cache.Put(100, 100)
+	require.Len(fired, 1)
+	cache.Put(101, 101)
+	require.Equal([]float64{0.9, 0.9}, fired)
+}
+
+func TestSizedCacheOnThreshold(t *testing.T) {
+	require := require.New(t)
+
+	cache := NewSizedCache(100, func(_, v int) int { return v })
+	var fired []float64
+	cache.OnThreshold(0.5, func(current float64) {
+		fired = append(fired, current)
+	})
+
+	cache.Put(1, 30)
+	require.Empty(fired)
+	cache.Put(2, 30)
+	cache.Put(3, 30)
+	require.Equal([]float64{0.6}, fired)
+
+	cache.Flush()
+	cache.Put(1, 50)
+	require.Equal([]float64{0.6, 0.5}, fired)
+}
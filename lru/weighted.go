@@ -0,0 +1,117 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package lru
+
+import (
+	"container/list"
+	"math/rand/v2"
+)
+
+// WeightedSample is the number of entries a weighted cache draws its
+// eviction victim from.
+//
+// A larger sample makes evictions follow the weights more closely, at the
+// cost of calling the weight function once more per candidate on every
+// eviction.
+const WeightedSample = 8
+
+// NewWeightedCache creates a cache that evicts randomly, so that a single
+// workload, such as one tenant scanning its keys, can't push out everyone
+// else's entries. On eviction, the least recently used entry and up to
+// WeightedSample-1 others picked pseudo-randomly from the whole cache are
+// candidates. Each is drawn with probability proportional to its staleness,
+// the number of accesses to the cache since its own last access, divided by
+// its weight, so stale entries are the likeliest victims and entries of a
+// heavier weight survive longer.
+//
+// Weights are relative: an entry of twice the weight must be twice as stale to
+// be as likely a victim. Since the entries of a tenant accessing the cache less
+// often also grow staler, balancing the cache between tenants takes weights
+// inversely proportional to about the square of each tenant's share of the
+// traffic. With a weight of 1 for a tenant making nine accesses for every one
+// of another, the other's entries need a weight of about 81 for it not to be
+// crowded out. An equal weight for every entry yields an approximate LRU.
+// Weights that aren't positive are treated as the smallest positive weight.
+//
+// weight is called with the cache's lock held and must not call back into the
+// cache. It is called once per candidate on every eviction, so it should be
+// cheap. Under Options.Deterministic the candidates are the WeightedSample
+// least recently used entries and the draw uses a fixed seed, so that
+// evictions are reproducible, at the cost of fairness across the whole cache.
+func NewWeightedCache[K comparable, V any](size int, weight func(K, V) float64) *Cache[K, V] {
+	return NewCacheWithOptions(Options[K, V]{
+		Size:   size,
+		Weight: weight,
+	})
+}
+
+// newWeightedRand returns the source of the draws of a weighted cache.
+func newWeightedRand(deterministic bool) *rand.Rand {
+	if deterministic {
+		return rand.New(rand.NewPCG(0, 0))
+	}
+	return rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+}
+
+// markUsed records an access of ent for the staleness of weighted eviction.
+func (c *Cache[K, V]) markUsed(ent *entry[K, V]) {
+	if c.weight != nil {
+		c.tick++
+		ent.used = c.tick
+	}
+}
+
+// weightedVictim draws the victim among oldest, the least recently used
+// unpinned element, and other unpinned candidates.
+func (c *Cache[K, V]) weightedVictim(oldest *list.Element) *list.Element {
+	var (
+		candidates [WeightedSample]*list.Element
+		scores     [WeightedSample]float64
+		n          int
+		total      float64
+	)
+	add := func(elem *list.Element) {
+		ent := elem.Value.(*entry[K, V])
+		w := c.weight(ent.key, ent.value)
+		if !(w > 0) {
+			w = minWeight
+		}
+		score := float64(c.tick-ent.used+1) / w
+		candidates[n], scores[n] = elem, score
+		total += score
+		n++
+	}
+
+	add(oldest)
+	if c.deterministic {
+		for elem := oldest.Prev(); elem != nil && n < WeightedSample; elem = elem.Prev() {
+			if !elem.Value.(*entry[K, V]).pinned {
+				add(elem)
+			}
+		}
+	} else {
+		// Map iteration starts at a random position.
+		for _, elem := range c.items {
+			if n == WeightedSample {
+				break
+			}
+			if elem != oldest && !elem.Value.(*entry[K, V]).pinned {
+				add(elem)
+			}
+		}
+	}
+
+	target := c.rng.Float64() * total
+	for i := range n {
+		if target < scores[i] {
+			return candidates[i]
+		}
+		target -= scores[i]
+	}
+	// Rounding can leave the target just past the last score.
+	return candidates[n-1]
+}
+
+// minWeight replaces the weights that aren't positive.
+const minWeight = 1e-9
@@ -0,0 +1,65 @@
+package lru
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// minorShare runs a workload of two tenants, each with its own keys, in which
+// the major tenant 0 makes nine accesses for each one of the minor tenant 1.
+// Values are the tenant of their key. It returns the share of the cache held
+// by the minor tenant, averaged over the second half of the run.
+func minorShare(c *Cache[int, int]) float64 {
+	const (
+		keysPerTenant = 1000
+		accesses      = 100_000
+		every         = 1000
+	)
+	var (
+		r       = rand.New(rand.NewPCG(1, 1))
+		share   float64
+		samples int
+	)
+	for i := range accesses {
+		tenant := 0
+		if r.IntN(10) == 0 {
+			tenant = 1
+		}
+		key := tenant*keysPerTenant + r.IntN(keysPerTenant)
+		if _, ok := c.Get(key); !ok {
+			c.Put(key, tenant)
+		}
+
+		if i >= accesses/2 && i%every == 0 {
+			var minor int
+			for _, tenant := range c.All() {
+				minor += tenant
+			}
+			share += float64(minor) / float64(c.Len())
+			samples++
+		}
+	}
+	return share / float64(samples)
+}
+
+func TestWeightedCacheBalancesTenants(t *testing.T) {
+	require := require.New(t)
+
+	// Under LRU, the major tenant's traffic crowds out the minor tenant.
+	require.Less(minorShare(NewCache[int, int](100)), 0.2)
+
+	// Weighing the minor tenant's entries by the square of the ratio of the
+	// tenants' traffic gives it a comparable share.
+	weighted := NewWeightedCache(100, func(_, tenant int) float64 {
+		if tenant == 0 {
+			return 1
+		}
+		return 81
+	})
+	share := minorShare(weighted)
+	require.Greater(share, 0.25)
+	require.Less(share, 0.75)
+	require.NoError(weighted.CheckInvariants())
+}
@@ -9,7 +9,10 @@ import (
 	"github.com/luxfi/math/linked"
 )
 
-var _ Cacher[struct{}, struct{}] = (*LRU[struct{}, struct{}])(nil)
+var (
+	_ Cacher[struct{}, struct{}] = (*LRU[struct{}, struct{}])(nil)
+	_ BoundReporter              = (*LRU[struct{}, struct{}])(nil)
+)
 
 // NewLRU creates a new LRU cache with the specified size.
 func NewLRU[K comparable, V any](size int) *LRU[K, V] {
@@ -70,6 +73,11 @@ func (c *LRU[_, _]) PortionFilled() float64 {
 	return c.portionFilled()
 }
 
+// Bounded returns true, as the cache holds at most Size entries.
+func (*LRU[_, _]) Bounded() bool {
+	return true
+}
+
 func (c *LRU[K, V]) put(key K, value V) {
 	c.resize()
 
@@ -0,0 +1,99 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metercacher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/luxfi/metric"
+
+	"github.com/luxfi/cache/bytecache"
+)
+
+// ByteCache publishes the [bytecache.Stats] of a [bytecache.Cache]. Unlike
+// [Cache] it does not wrap the cache's methods, as bytecache is not a
+// [cache.Cacher]; the metrics are instead refreshed from the stats by
+// [ByteCache.Collect].
+type ByteCache struct {
+	*bytecache.Cache
+
+	entries  metric.Gauge
+	bytes    metric.Gauge
+	hitRatio metric.Gauge
+	misses   metric.Counter
+
+	// mu serializes Collect, and lastMisses is the miss count it last
+	// published, so that the counter only ever grows by the difference.
+	mu         sync.Mutex
+	lastMisses uint64
+}
+
+// NewMetered registers the metrics of c under namespace and publishes its
+// current stats. Call [ByteCache.Collect] or [ByteCache.Run] to refresh them.
+func NewMetered(
+	namespace string,
+	registry metric.Registry,
+	c *bytecache.Cache,
+) (*ByteCache, error) {
+	metricsInstance := metric.NewWithRegistry(namespace, registry)
+
+	m := &ByteCache{
+		Cache: c,
+		entries: metricsInstance.NewGauge(
+			"entries",
+			"number of entries",
+		),
+		bytes: metricsInstance.NewGauge(
+			"bytes",
+			"number of bytes held by keys and values",
+		),
+		hitRatio: metricsInstance.NewGauge(
+			"hit_ratio",
+			"fraction of get calls that were hits",
+		),
+		misses: metricsInstance.NewCounter(
+			"miss_count",
+			"number of get calls that missed",
+		),
+	}
+	m.Collect()
+	return m, nil
+}
+
+// Collect reads the stats of the cache and updates the metrics. It read-locks
+// every shard in turn, as [bytecache.Cache.UpdateStats] does.
+func (m *ByteCache) Collect() {
+	var stats bytecache.Stats
+	m.Cache.UpdateStats(&stats)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries.Set(float64(stats.EntriesCount))
+	m.bytes.Set(float64(stats.BytesSize))
+	if stats.GetCalls > 0 {
+		hits := stats.GetCalls - min(stats.Misses, stats.GetCalls)
+		m.hitRatio.Set(float64(hits) / float64(stats.GetCalls))
+	}
+	if stats.Misses > m.lastMisses {
+		m.misses.Add(float64(stats.Misses - m.lastMisses))
+		m.lastMisses = stats.Misses
+	}
+}
+
+// Run calls Collect every interval until ctx is done.
+func (m *ByteCache) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Collect()
+		}
+	}
+}
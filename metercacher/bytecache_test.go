@@ -0,0 +1,67 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metercacher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/metric"
+
+	"github.com/luxfi/cache/bytecache"
+)
+
+func gatherValues(t *testing.T, registry metric.Registry) map[string]float64 {
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			values[family.GetName()] += m.GetGauge().GetValue() + m.GetCounter().GetValue()
+		}
+	}
+	return values
+}
+
+func TestByteCacheMetrics(t *testing.T) {
+	require := require.New(t)
+
+	registry := metric.NewRegistry()
+	c, err := NewMetered("bytes", registry, bytecache.New(1<<20))
+	require.NoError(err)
+
+	values := gatherValues(t, registry)
+	require.Zero(values["bytes_entries"])
+	require.Zero(values["bytes_miss_count"])
+
+	c.Set([]byte("a"), []byte("12345"))
+	c.Set([]byte("b"), []byte("678"))
+	require.Equal([]byte("12345"), c.Get(nil, []byte("a")))
+	require.Empty(c.Get(nil, []byte("c")))
+	require.Empty(c.Get(nil, []byte("d")))
+	require.Equal([]byte("678"), c.Get(nil, []byte("b")))
+
+	// Metrics only change when collected.
+	require.Zero(gatherValues(t, registry)["bytes_entries"])
+
+	c.Collect()
+	var stats bytecache.Stats
+	c.UpdateStats(&stats)
+	values = gatherValues(t, registry)
+	require.Equal(2.0, values["bytes_entries"])
+	require.Equal(float64(stats.BytesSize), values["bytes_bytes"])
+	require.Equal(0.5, values["bytes_hit_ratio"])
+	require.Equal(2.0, values["bytes_miss_count"])
+
+	// Collecting again without new misses leaves the counter alone.
+	c.Collect()
+	require.Equal(2.0, gatherValues(t, registry)["bytes_miss_count"])
+
+	c.Get(nil, []byte("e"))
+	c.Collect()
+	values = gatherValues(t, registry)
+	require.Equal(3.0, values["bytes_miss_count"])
+	require.Equal(0.4, values["bytes_hit_ratio"])
+}
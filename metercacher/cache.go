@@ -4,6 +4,7 @@
 package metercacher
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/luxfi/metric"
@@ -11,12 +12,37 @@ import (
 	"github.com/luxfi/cache"
 )
 
-var _ cache.Cacher[struct{}, struct{}] = (*Cache[struct{}, struct{}])(nil)
+var (
+	_ cache.Cacher[struct{}, struct{}] = (*Cache[struct{}, struct{}])(nil)
+	_ cache.StatsReporter              = (*Cache[struct{}, struct{}])(nil)
+	_ cache.BoundReporter              = (*Cache[struct{}, struct{}])(nil)
+)
+
+// evictionAgeReporter is implemented by caches that can report the age of the
+// entries they evict, such as lru.Cache.
+type evictionAgeReporter interface {
+	SetEvictionAgeObserver(observe func(age time.Duration))
+}
+
+// capacityReporter is implemented by bounded caches, such as lru.Cache, that
+// report their capacity.
+type capacityReporter interface {
+	Cap() int
+}
 
 type Cache[K comparable, V any] struct {
 	cache.Cacher[K, V]
 
 	metrics *cacheMetrics
+
+	// hits and misses count the Get calls made through the wrapper.
+	hits   int64
+	misses int64
+
+	// slow, if set, reports the calls slower than its threshold, counted by
+	// slowOps.
+	slow    atomic.Pointer[slowConfig]
+	slowOps int64
 }
 
 func New[K comparable, V any](
@@ -25,32 +51,83 @@ func New[K comparable, V any](
 	cache cache.Cacher[K, V],
 ) (*Cache[K, V], error) {
 	metrics, err := newMetrics(namespace, registry)
+	observeEvictionAge(cache, metrics)
+	return &Cache[K, V]{
+		Cacher:  cache,
+		metrics: metrics,
+	}, err
+}
+
+// NewNamed returns a metered cache whose metrics all carry a constant "name"
+// label set to [name]. This allows multiple caches to be registered under the
+// same namespace and registry while remaining distinguishable.
+//
+// [name] should be a static identifier; it is not intended to carry
+// per-request or otherwise unbounded values.
+func NewNamed[K comparable, V any](
+	namespace string,
+	name string,
+	registry metric.Registry,
+	cache cache.Cacher[K, V],
+) (*Cache[K, V], error) {
+	metrics, err := newNamedMetrics(namespace, name, registry)
+	observeEvictionAge(cache, metrics)
 	return &Cache[K, V]{
 		Cacher:  cache,
 		metrics: metrics,
 	}, err
 }
 
+// WithMetrics wraps c with metrics registered under namespace and returns the
+// result as a plain [cache.Cacher], so that metering can be enabled with a
+// single line and composed with other wrappers. It is equivalent to New.
+func WithMetrics[K comparable, V any](
+	c cache.Cacher[K, V],
+	namespace string,
+	registry metric.Registry,
+) (cache.Cacher[K, V], error) {
+	metered, err := New(namespace, registry, c)
+	if err != nil {
+		return nil, err
+	}
+	return metered, nil
+}
+
+// observeEvictionAge records the eviction ages reported by cache, if it
+// supports reporting them, into the eviction age histogram. An observer set
+// with lru.Options.OnEvictionAge keeps being called.
+func observeEvictionAge[K comparable, V any](cache cache.Cacher[K, V], metrics *cacheMetrics) {
+	if reporter, ok := cache.(evictionAgeReporter); ok {
+		reporter.SetEvictionAgeObserver(func(age time.Duration) {
+			metrics.evictionAge.Observe(age.Seconds())
+		})
+	}
+}
+
 func (c *Cache[K, V]) Put(key K, value V) {
 	start := time.Now()
 	c.Cacher.Put(key, value)
 	putDuration := time.Since(start)
+	c.observeSlow(opPut, putDuration)
 
 	c.metrics.putCount.Inc()
 	c.metrics.putTime.Add(float64(putDuration))
 	c.metrics.len.Set(float64(c.Cacher.Len()))
-	c.metrics.portionFilled.Set(c.Cacher.PortionFilled())
+	c.updatePortionFilled()
 }
 
 func (c *Cache[K, V]) Get(key K) (V, bool) {
 	start := time.Now()
 	value, has := c.Cacher.Get(key)
 	getDuration := time.Since(start)
+	c.observeSlow(opGet, getDuration)
 
 	if has {
+		atomic.AddInt64(&c.hits, 1)
 		c.metrics.getCount.With(hitLabels).Inc()
 		c.metrics.getTime.With(hitLabels).Add(float64(getDuration))
 	} else {
+		atomic.AddInt64(&c.misses, 1)
 		c.metrics.getCount.With(missLabels).Inc()
 		c.metrics.getTime.With(missLabels).Add(float64(getDuration))
 	}
@@ -59,15 +136,54 @@ func (c *Cache[K, V]) Get(key K) (V, bool) {
 }
 
 func (c *Cache[K, _]) Evict(key K) {
+	start := c.slowStart()
 	c.Cacher.Evict(key)
+	c.observeSlowSince(opEvict, start)
 
 	c.metrics.len.Set(float64(c.Cacher.Len()))
-	c.metrics.portionFilled.Set(c.Cacher.PortionFilled())
+	c.updatePortionFilled()
 }
 
 func (c *Cache[_, _]) Flush() {
+	start := c.slowStart()
 	c.Cacher.Flush()
+	c.observeSlowSince(opFlush, start)
 
 	c.metrics.len.Set(float64(c.Cacher.Len()))
-	c.metrics.portionFilled.Set(c.Cacher.PortionFilled())
+	c.updatePortionFilled()
+}
+
+// Stats returns the hits and misses of the Get calls made through the wrapper.
+// Evictions are taken from the wrapped cache if it is a [cache.StatsReporter],
+// and the capacity if it reports one; otherwise they are [cache.Untracked].
+func (c *Cache[_, _]) Stats() cache.Stats {
+	stats := cache.Stats{
+		Evictions:     cache.Untracked,
+		Len:           c.Cacher.Len(),
+		Cap:           cache.Untracked,
+		PortionFilled: c.Cacher.PortionFilled(),
+	}
+	if reporter, ok := c.Cacher.(cache.StatsReporter); ok {
+		inner := reporter.Stats()
+		stats.Evictions = inner.Evictions
+		stats.Cap = inner.Cap
+	} else if reporter, ok := c.Cacher.(capacityReporter); ok {
+		stats.Cap = reporter.Cap()
+	}
+	stats.Hits = atomic.LoadInt64(&c.hits)
+	stats.Misses = atomic.LoadInt64(&c.misses)
+	return stats
+}
+
+// updatePortionFilled sets the fill gauge, which is left at zero for unbounded
+// caches as their PortionFilled is not meaningful.
+func (c *Cache[_, _]) updatePortionFilled() {
+	if c.Bounded() {
+		c.metrics.portionFilled.Set(c.Cacher.PortionFilled())
+	}
+}
+
+// Bounded reports whether the wrapped cache is bounded, as [cache.IsBounded].
+func (c *Cache[_, _]) Bounded() bool {
+	return cache.IsBounded(c.Cacher)
 }
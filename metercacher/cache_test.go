@@ -0,0 +1,214 @@
+// Copyright (C) 2019-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metercacher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/metric"
+
+	"github.com/luxfi/cache"
+	"github.com/luxfi/cache/lru"
+)
+
+func TestNewNamedLabelsMetrics(t *testing.T) {
+	require := require.New(t)
+
+	registry := metric.NewRegistry()
+	headers, err := NewNamed("chain", "headers", registry, cache.NewLRU[int, int](2))
+	require.NoError(err)
+	bodies, err := NewNamed("chain", "bodies", registry, cache.NewLRU[int, int](2))
+	require.NoError(err)
+
+	headers.Put(1, 1)
+	_, _ = headers.Get(1)
+	bodies.Put(1, 1)
+	_, _ = bodies.Get(2)
+
+	families, err := registry.Gather()
+	require.NoError(err)
+	require.NotEmpty(families)
+
+	for _, family := range families {
+		names := make(map[string]struct{})
+		for _, m := range family.GetMetric() {
+			var found bool
+			for _, label := range m.GetLabel() {
+				if label.GetName() == nameLabel {
+					names[label.GetValue()] = struct{}{}
+					found = true
+				}
+			}
+			require.True(found, "metric %s is missing the %q label", family.GetName(), nameLabel)
+		}
+		require.Contains(names, "headers", family.GetName())
+		require.Contains(names, "bodies", family.GetName())
+	}
+}
+
+func TestNewNamedDuplicateName(t *testing.T) {
+	registry := metric.NewRegistry()
+	_, err := NewNamed("chain", "headers", registry, cache.NewLRU[int, int](2))
+	require.NoError(t, err)
+	_, err = NewNamed("chain", "headers", registry, cache.NewLRU[int, int](2))
+	require.Error(t, err)
+}
+
+func TestNewUnnamed(t *testing.T) {
+	require := require.New(t)
+
+	registry := metric.NewRegistry()
+	c, err := New("chain", registry, cache.NewLRU[int, int](2))
+	require.NoError(err)
+	c.Put(1, 1)
+
+	families, err := registry.Gather()
+	require.NoError(err)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				require.NotEqual(nameLabel, label.GetName())
+			}
+		}
+	}
+}
+
+func TestEvictionAgeHistogram(t *testing.T) {
+	require := require.New(t)
+
+	registry := metric.NewRegistry()
+	c, err := New[int, int]("chain", registry, lru.NewCache[int, int](1))
+	require.NoError(err)
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Put(3, 3)
+
+	families, err := registry.Gather()
+	require.NoError(err)
+	var found bool
+	for _, family := range families {
+		if family.GetName() != "chain_eviction_age" {
+			continue
+		}
+		found = true
+		require.Equal(uint64(2), family.GetMetric()[0].GetHistogram().GetSampleCount())
+	}
+	require.True(found)
+}
+
+func TestEvictionAgeKeepsUserObserver(t *testing.T) {
+	require := require.New(t)
+
+	var observed int
+	inner := lru.NewCacheWithOptions(lru.Options[int, int]{
+		Size:          1,
+		OnEvictionAge: func(time.Duration) { observed++ },
+	})
+	registry := metric.NewRegistry()
+	c, err := New[int, int]("chain", registry, inner)
+	require.NoError(err)
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Put(3, 3)
+	require.Equal(2, observed)
+
+	families, err := registry.Gather()
+	require.NoError(err)
+	var found bool
+	for _, family := range families {
+		if family.GetName() == "chain_eviction_age" {
+			found = true
+			require.Equal(uint64(2), family.GetMetric()[0].GetHistogram().GetSampleCount())
+		}
+	}
+	require.True(found)
+}
+
+func TestWithMetrics(t *testing.T) {
+	require := require.New(t)
+
+	registry := metric.NewRegistry()
+	c, err := WithMetrics(cache.NewLRU[int, int](2), "chain", registry)
+	require.NoError(err)
+
+	c.Put(1, 1)
+	v, ok := c.Get(1)
+	require.True(ok)
+	require.Equal(1, v)
+
+	families, err := registry.Gather()
+	require.NoError(err)
+	counts := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			counts[family.GetName()] += m.GetCounter().GetValue()
+		}
+	}
+	require.Equal(1.0, counts["chain_put_count"])
+	require.Equal(1.0, counts["chain_get_count"])
+}
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name      string
+		inner     cache.Cacher[int, int]
+		evictions int64
+		cap       int
+	}{
+		{
+			name:      "stats reporter",
+			inner:     lru.NewSizedCache[int, int](2, nil),
+			evictions: cache.Untracked,
+			cap:       2,
+		},
+		{
+			name:      "capacity reporter",
+			inner:     lru.NewCache[int, int](2),
+			evictions: cache.Untracked,
+			cap:       2,
+		},
+		{
+			name:      "plain cacher",
+			inner:     cache.NewLRU[int, int](2),
+			evictions: cache.Untracked,
+			cap:       cache.Untracked,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			c, err := New("chain", metric.NewRegistry(), test.inner)
+			require.NoError(err)
+			c.Put(1, 1)
+			_, _ = c.Get(1)
+			_, _ = c.Get(1)
+			_, _ = c.Get(2)
+
+			require.Equal(cache.Stats{
+				Hits:          2,
+				Misses:        1,
+				Evictions:     test.evictions,
+				Len:           1,
+				Cap:           test.cap,
+				PortionFilled: 0.5,
+			}, c.Stats())
+		})
+	}
+}
+
+func TestBounded(t *testing.T) {
+	require := require.New(t)
+
+	bounded, err := New("bounded", metric.NewRegistry(), lru.NewCache[int, int](2))
+	require.NoError(err)
+	require.True(bounded.Bounded())
+
+	unbounded, err := New("unbounded", metric.NewRegistry(), cache.NewDualMapCache[int, int](nil))
+	require.NoError(err)
+	require.False(unbounded.Bounded())
+}
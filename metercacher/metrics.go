@@ -3,15 +3,29 @@
 
 package metercacher
 
-import "github.com/luxfi/metric"
+import (
+	"errors"
+
+	"github.com/luxfi/metric"
+)
 
 const (
 	resultLabel = "result"
 	hitResult   = "hit"
 	missResult  = "miss"
+
+	// nameLabel is attached as a constant label to every metric of a named
+	// cache so that multiple caches can share a namespace.
+	nameLabel = "name"
+
+	opLabel = "op"
 )
 
 var (
+	// evictionAgeBuckets are the upper bounds, in seconds, of the eviction age
+	// histogram, ranging from thrashing to long lived entries.
+	evictionAgeBuckets = []float64{.001, .01, .1, 1, 10, 60, 300, 900, 3600}
+
 	resultLabels = []string{resultLabel}
 	hitLabels    = metric.Labels{
 		resultLabel: hitResult,
@@ -19,6 +33,8 @@ var (
 	missLabels = metric.Labels{
 		resultLabel: missResult,
 	}
+
+	opLabels = []string{opLabel}
 )
 
 type cacheMetrics struct {
@@ -30,6 +46,10 @@ type cacheMetrics struct {
 
 	len           metric.Gauge
 	portionFilled metric.Gauge
+
+	evictionAge metric.Histogram
+
+	slowOps metric.CounterVec
 }
 
 func newMetrics(
@@ -65,6 +85,96 @@ func newMetrics(
 			"portion_filled",
 			"fraction of cache filled",
 		),
+		evictionAge: metricsInstance.NewHistogram(
+			"eviction_age",
+			"time (s) since last access of entries evicted for capacity",
+			evictionAgeBuckets,
+		),
+		slowOps: metricsInstance.NewCounterVec(
+			"slow_op_count",
+			"number of calls slower than the slow threshold",
+			opLabels,
+		),
 	}
 	return m, nil
 }
+
+// newNamedMetrics creates the same metrics as newMetrics, but with a constant
+// [nameLabel] label set to [name]. Because the label value is fixed per cache,
+// registering several named caches under one namespace only adds one series
+// per cache.
+func newNamedMetrics(
+	namespace string,
+	name string,
+	registry metric.Registry,
+) (*cacheMetrics, error) {
+	constLabels := metric.PrometheusLabels{
+		nameLabel: name,
+	}
+
+	m := &cacheMetrics{
+		getCount: metric.NewCounterVec(metric.CounterOpts{
+			Namespace:   namespace,
+			Name:        "get_count",
+			Help:        "number of get calls",
+			ConstLabels: constLabels,
+		}, resultLabels),
+		getTime: metric.NewGaugeVec(metric.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "get_time",
+			Help:        "time spent (ns) in get calls",
+			ConstLabels: constLabels,
+		}, resultLabels),
+		putCount: metric.NewCounterWithOpts(metric.CounterOpts{
+			Namespace:   namespace,
+			Name:        "put_count",
+			Help:        "number of put calls",
+			ConstLabels: constLabels,
+		}),
+		putTime: metric.NewGaugeWithOpts(metric.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "put_time",
+			Help:        "time spent (ns) in put calls",
+			ConstLabels: constLabels,
+		}),
+		len: metric.NewGaugeWithOpts(metric.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "len",
+			Help:        "number of entries",
+			ConstLabels: constLabels,
+		}),
+		portionFilled: metric.NewGaugeWithOpts(metric.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "portion_filled",
+			Help:        "fraction of cache filled",
+			ConstLabels: constLabels,
+		}),
+		evictionAge: metric.NewHistogram(metric.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "eviction_age",
+			Help:        "time (s) since last access of entries evicted for capacity",
+			ConstLabels: constLabels,
+			Buckets:     evictionAgeBuckets,
+		}),
+		slowOps: metric.NewCounterVec(metric.CounterOpts{
+			Namespace:   namespace,
+			Name:        "slow_op_count",
+			Help:        "number of calls slower than the slow threshold",
+			ConstLabels: constLabels,
+		}, opLabels),
+	}
+	if registry == nil {
+		return m, nil
+	}
+
+	return m, errors.Join(
+		registry.Register(metric.AsCollector(m.getCount)),
+		registry.Register(metric.AsCollector(m.getTime)),
+		registry.Register(metric.AsCollector(m.putCount)),
+		registry.Register(metric.AsCollector(m.putTime)),
+		registry.Register(metric.AsCollector(m.len)),
+		registry.Register(metric.AsCollector(m.portionFilled)),
+		registry.Register(metric.AsCollector(m.evictionAge)),
+		registry.Register(metric.AsCollector(m.slowOps)),
+	)
+}
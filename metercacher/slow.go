@@ -0,0 +1,92 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metercacher
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/luxfi/metric"
+)
+
+// The operations reported to the callback of SetSlowThreshold and counted by
+// the slow_op_count metric.
+const (
+	opGet   = "get"
+	opPut   = "put"
+	opEvict = "evict"
+	opFlush = "flush"
+)
+
+// slowOpLabels holds the labels of the slow_op_count series of each operation.
+var slowOpLabels = map[string]metric.Labels{
+	opGet:   {opLabel: opGet},
+	opPut:   {opLabel: opPut},
+	opEvict: {opLabel: opEvict},
+	opFlush: {opLabel: opFlush},
+}
+
+// slowConfig is the configuration set by SetSlowThreshold.
+type slowConfig struct {
+	threshold time.Duration
+	onSlow    func(op string, d time.Duration)
+}
+
+// SetSlowThreshold reports the calls to Get, Put, Evict and Flush that take
+// longer than threshold, such as those stalled by garbage collection or lock
+// contention, which the time gauges average away. Each is counted by SlowOps
+// and by the slow_op_count metric, labeled by op, and passed to onSlow, if not
+// nil, with the name of the operation, one of "get", "put", "evict" and
+// "flush", and its duration. onSlow is called on the goroutine that made the
+// call, before the call returns, so it should be quick, such as logging.
+//
+// A threshold <= 0 stops reporting, which is the default. Evict and Flush are
+// only timed while a threshold is set. SetSlowThreshold may be called while
+// the cache is in use.
+func (c *Cache[_, _]) SetSlowThreshold(threshold time.Duration, onSlow func(op string, d time.Duration)) {
+	if threshold <= 0 {
+		c.slow.Store(nil)
+		return
+	}
+	c.slow.Store(&slowConfig{
+		threshold: threshold,
+		onSlow:    onSlow,
+	})
+}
+
+// SlowOps returns the number of calls made through the wrapper that exceeded
+// the slow threshold.
+func (c *Cache[_, _]) SlowOps() int64 {
+	return atomic.LoadInt64(&c.slowOps)
+}
+
+// slowStart returns the start time of a call timed only to report it if it is
+// slow, or the zero time if no threshold is set.
+func (c *Cache[_, _]) slowStart() time.Time {
+	if c.slow.Load() == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// observeSlowSince reports the call to op that started at start, as returned
+// by slowStart.
+func (c *Cache[_, _]) observeSlowSince(op string, start time.Time) {
+	if !start.IsZero() {
+		c.observeSlow(op, time.Since(start))
+	}
+}
+
+// observeSlow reports the call to op that took d if it exceeded the threshold.
+func (c *Cache[_, _]) observeSlow(op string, d time.Duration) {
+	slow := c.slow.Load()
+	if slow == nil || d <= slow.threshold {
+		return
+	}
+	atomic.AddInt64(&c.slowOps, 1)
+	c.metrics.slowOps.With(slowOpLabels[op]).Inc()
+	if slow.onSlow != nil {
+		slow.onSlow(op, d)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metercacher
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/metric"
+
+	"github.com/luxfi/cache"
+)
+
+// slowCache delays the Puts of a cache by delay.
+type slowCache struct {
+	cache.Cacher[int, int]
+	delay time.Duration
+}
+
+func (c *slowCache) Put(key, value int) {
+	time.Sleep(c.delay)
+	c.Cacher.Put(key, value)
+}
+
+func TestSlowThreshold(t *testing.T) {
+	require := require.New(t)
+
+	registry := metric.NewRegistry()
+	c, err := New[int, int]("slow", registry, &slowCache{
+		Cacher: cache.NewLRU[int, int](2),
+		delay:  20 * time.Millisecond,
+	})
+	require.NoError(err)
+
+	// Nothing is reported without a threshold.
+	c.Put(1, 1)
+	require.Zero(c.SlowOps())
+
+	var (
+		mu   sync.Mutex
+		slow []string
+	)
+	c.SetSlowThreshold(10*time.Millisecond, func(op string, d time.Duration) {
+		require.GreaterOrEqual(d, 10*time.Millisecond)
+		mu.Lock()
+		slow = append(slow, op)
+		mu.Unlock()
+	})
+	c.Put(2, 2)
+	_, _ = c.Get(2)
+	c.Evict(2)
+	c.Flush()
+	require.Equal(int64(1), c.SlowOps())
+	require.Equal([]string{opPut}, slow)
+	require.Equal(1.0, gatherValues(t, registry)["slow_slow_op_count"])
+
+	c.SetSlowThreshold(0, nil)
+	c.Put(3, 3)
+	require.Equal(int64(1), c.SlowOps())
+	require.Len(slow, 1)
+}
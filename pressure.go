@@ -0,0 +1,182 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"math"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHighWatermark is the fraction of the memory limit above which a
+	// PressureController starts evicting.
+	DefaultHighWatermark = 0.9
+	// DefaultLowWatermark is the fraction of the memory limit below which a
+	// PressureController stops evicting.
+	DefaultLowWatermark = 0.75
+	// DefaultEvictFraction is the fraction of the entries a PressureController
+	// evicts per sample while memory is under pressure.
+	DefaultEvictFraction = 0.1
+	// DefaultPressureInterval is the time between the samples of a
+	// PressureController.
+	DefaultPressureInterval = time.Second
+)
+
+// OldestEvicter is a cache that can evict its least valuable entries on
+// demand, such as lru.Cache.
+type OldestEvicter[K comparable, V any] interface {
+	Cacher[K, V]
+	// EvictOldest evicts up to n entries in eviction order and returns the
+	// number removed.
+	EvictOldest(n int) int
+}
+
+// MemoryUsage is a sample of the memory used by the process, in bytes.
+type MemoryUsage struct {
+	// Used is the memory counted against Limit.
+	Used uint64
+	// Limit is the memory the process should stay under, or 0 if there is
+	// none.
+	Limit uint64
+}
+
+// ReadRuntimeMemory reports the memory mapped by the Go runtime, less what it
+// has returned to the OS, against the soft memory limit set by GOMEMLIMIT or
+// debug.SetMemoryLimit. This is the quantity the garbage collector compares
+// against the limit. Limit is 0 if no limit is set. Unlike
+// runtime.ReadMemStats, it doesn't stop the world.
+func ReadRuntimeMemory() MemoryUsage {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(samples)
+	usage := MemoryUsage{
+		Used: samples[0].Value.Uint64() - samples[1].Value.Uint64(),
+	}
+	if limit := debug.SetMemoryLimit(-1); limit != math.MaxInt64 {
+		usage.Limit = uint64(limit)
+	}
+	return usage
+}
+
+// PressureConfig configures a PressureController. Zero or invalid fields take
+// their default values.
+type PressureConfig struct {
+	// HighWatermark is the fraction of the limit above which the controller
+	// starts evicting. It defaults to DefaultHighWatermark.
+	HighWatermark float64
+	// LowWatermark is the fraction of the limit the controller evicts down
+	// to once it started. It defaults to DefaultLowWatermark, or to
+	// HighWatermark if that is lower.
+	LowWatermark float64
+	// EvictFraction is the fraction of the entries evicted per sample while
+	// usage is between the watermarks. It defaults to DefaultEvictFraction.
+	EvictFraction float64
+	// Interval is the time between samples. It defaults to
+	// DefaultPressureInterval.
+	Interval time.Duration
+	// ReadMemory samples the memory usage. It defaults to ReadRuntimeMemory.
+	ReadMemory func() MemoryUsage
+}
+
+// PressureController shrinks a cache when the process approaches its memory
+// limit. Every interval it samples the memory usage, and once the usage
+// exceeds the high watermark it evicts a fraction of the cache's entries per
+// sample until the usage drops below the low watermark. Evicted entries are
+// only reclaimed by a later garbage collection, which the runtime triggers
+// itself as the usage nears the limit, so the watermarks should leave room
+// for that lag. Nothing is evicted while there is no limit.
+type PressureController struct {
+	config   PressureConfig
+	len      func() int
+	evict    func(n int) int
+	evicting bool
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewPressureController starts a PressureController shrinking c. Close must be
+// called to stop it.
+func NewPressureController[K comparable, V any](c OldestEvicter[K, V], config PressureConfig) *PressureController {
+	p := newPressureController(c, config)
+	go p.run()
+	return p
+}
+
+func newPressureController[K comparable, V any](c OldestEvicter[K, V], config PressureConfig) *PressureController {
+	if config.HighWatermark <= 0 || config.HighWatermark > 1 {
+		config.HighWatermark = DefaultHighWatermark
+	}
+	if config.LowWatermark <= 0 || config.LowWatermark > 1 {
+		config.LowWatermark = DefaultLowWatermark
+	}
+	config.LowWatermark = min(config.LowWatermark, config.HighWatermark)
+	if config.EvictFraction <= 0 || config.EvictFraction > 1 {
+		config.EvictFraction = DefaultEvictFraction
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultPressureInterval
+	}
+	if config.ReadMemory == nil {
+		config.ReadMemory = ReadRuntimeMemory
+	}
+	return &PressureController{
+		config: config,
+		len:    c.Len,
+		evict:  c.EvictOldest,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+func (p *PressureController) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.sample()
+		}
+	}
+}
+
+// sample checks the memory usage once and returns the number of entries
+// evicted in response.
+func (p *PressureController) sample() int {
+	usage := p.config.ReadMemory()
+	if usage.Limit == 0 {
+		p.evicting = false
+		return 0
+	}
+	used := float64(usage.Used) / float64(usage.Limit)
+	switch {
+	case used > p.config.HighWatermark:
+		p.evicting = true
+	case used < p.config.LowWatermark:
+		p.evicting = false
+	}
+	if !p.evicting {
+		return 0
+	}
+	n := max(int(float64(p.len())*p.config.EvictFraction), 1)
+	return p.evict(n)
+}
+
+// Close stops the controller and waits for its sampling goroutine to exit. It
+// is safe to call Close more than once.
+func (p *PressureController) Close() {
+	p.once.Do(func() {
+		close(p.stop)
+	})
+	<-p.done
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"runtime/debug"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// shrinkingCache is an OldestEvicter whose entries are counted but not
+// stored.
+type shrinkingCache struct {
+	*Empty[int, int]
+	len     int
+	evicted []int
+}
+
+func (c *shrinkingCache) Len() int {
+	return c.len
+}
+
+func (c *shrinkingCache) EvictOldest(n int) int {
+	n = min(n, c.len)
+	c.len -= n
+	c.evicted = append(c.evicted, n)
+	return n
+}
+
+func TestPressureControllerWatermarks(t *testing.T) {
+	require := require.New(t)
+
+	var used uint64
+	c := &shrinkingCache{Empty: &Empty[int, int]{}, len: 1000}
+	p := newPressureController[int, int](c, PressureConfig{
+		HighWatermark: 0.8,
+		LowWatermark:  0.5,
+		EvictFraction: 0.1,
+		ReadMemory: func() MemoryUsage {
+			return MemoryUsage{Used: used, Limit: 100}
+		},
+	})
+
+	steps := []struct {
+		used    uint64
+		evicted int
+	}{
+		{used: 70, evicted: 0},   // below the high watermark
+		{used: 90, evicted: 100}, // crossed the high watermark
+		{used: 70, evicted: 90},  // still above the low watermark
+		{used: 60, evicted: 81},
+		{used: 40, evicted: 0}, // dropped below the low watermark
+		{used: 70, evicted: 0},
+	}
+	for _, step := range steps {
+		used = step.used
+		require.Equal(step.evicted, p.sample())
+	}
+	require.Equal([]int{100, 90, 81}, c.evicted)
+	require.Equal(729, c.len)
+}
+
+func TestPressureControllerNoLimit(t *testing.T) {
+	c := &shrinkingCache{Empty: &Empty[int, int]{}, len: 10}
+	p := newPressureController[int, int](c, PressureConfig{
+		ReadMemory: func() MemoryUsage {
+			return MemoryUsage{Used: 1 << 40}
+		},
+	})
+	require.Zero(t, p.sample())
+	require.Empty(t, c.evicted)
+}
+
+func TestPressureControllerClose(t *testing.T) {
+	require := require.New(t)
+
+	var samples atomic.Int64
+	c := &shrinkingCache{Empty: &Empty[int, int]{}}
+	p := NewPressureController[int, int](c, PressureConfig{
+		Interval: time.Millisecond,
+		ReadMemory: func() MemoryUsage {
+			samples.Add(1)
+			return MemoryUsage{}
+		},
+	})
+	require.Eventually(func() bool {
+		return samples.Load() >= 3
+	}, time.Second, time.Millisecond)
+
+	p.Close()
+	p.Close()
+	stopped := samples.Load()
+	time.Sleep(5 * time.Millisecond)
+	require.Equal(stopped, samples.Load())
+}
+
+func TestReadRuntimeMemory(t *testing.T) {
+	require := require.New(t)
+
+	require.NotZero(ReadRuntimeMemory().Used)
+
+	defer debug.SetMemoryLimit(debug.SetMemoryLimit(1 << 40))
+	require.Equal(uint64(1<<40), ReadRuntimeMemory().Limit)
+}
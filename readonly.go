@@ -0,0 +1,67 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+var (
+	_ Cacher[struct{}, struct{}] = (*readOnly[struct{}, struct{}])(nil)
+	_ BoundReporter              = (*readOnly[struct{}, struct{}])(nil)
+)
+
+type readOnly[K comparable, V any] struct {
+	cache        Cacher[K, V]
+	panicOnWrite bool
+}
+
+// ReadOnly returns a view of c that delegates Get, Len and PortionFilled and
+// silently ignores Put, Evict and Flush. Unlike freezing a cache, the
+// underlying cache remains writable by whoever holds it directly.
+//
+// Reads are still delegated as is, so a Get may update the recency order of c.
+func ReadOnly[K comparable, V any](c Cacher[K, V]) Cacher[K, V] {
+	return &readOnly[K, V]{cache: c}
+}
+
+// ReadOnlyPanicking is like ReadOnly, but mutations through the view panic
+// instead of being ignored. This is useful to surface accidental writes in
+// tests and debug builds.
+func ReadOnlyPanicking[K comparable, V any](c Cacher[K, V]) Cacher[K, V] {
+	return &readOnly[K, V]{
+		cache:        c,
+		panicOnWrite: true,
+	}
+}
+
+func (r *readOnly[K, V]) Put(K, V) {
+	r.rejectWrite()
+}
+
+func (r *readOnly[K, V]) Get(key K) (V, bool) {
+	return r.cache.Get(key)
+}
+
+func (r *readOnly[K, _]) Evict(K) {
+	r.rejectWrite()
+}
+
+func (r *readOnly[_, _]) Flush() {
+	r.rejectWrite()
+}
+
+func (r *readOnly[_, _]) Len() int {
+	return r.cache.Len()
+}
+
+func (r *readOnly[_, _]) PortionFilled() float64 {
+	return r.cache.PortionFilled()
+}
+
+func (r *readOnly[_, _]) Bounded() bool {
+	return IsBounded(r.cache)
+}
+
+func (r *readOnly[_, _]) rejectWrite() {
+	if r.panicOnWrite {
+		panic("cache: write to read-only view")
+	}
+}
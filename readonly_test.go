@@ -0,0 +1,49 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnly(t *testing.T) {
+	require := require.New(t)
+
+	c := NewLRU[int, int](2)
+	c.Put(1, 1)
+	view := ReadOnly[int, int](c)
+
+	view.Put(2, 2)
+	view.Evict(1)
+	view.Flush()
+	require.Equal(1, c.Len())
+	_, ok := c.Get(2)
+	require.False(ok)
+
+	v, ok := view.Get(1)
+	require.True(ok)
+	require.Equal(1, v)
+	require.Equal(1, view.Len())
+	require.Equal(c.PortionFilled(), view.PortionFilled())
+
+	// Writes to the underlying cache are visible through the view.
+	c.Put(3, 3)
+	require.Equal(2, view.Len())
+}
+
+func TestReadOnlyPanicking(t *testing.T) {
+	require := require.New(t)
+
+	c := NewLRU[int, int](2)
+	view := ReadOnlyPanicking[int, int](c)
+	require.Panics(func() { view.Put(1, 1) })
+	require.Panics(func() { view.Evict(1) })
+	require.Panics(func() { view.Flush() })
+	require.Zero(c.Len())
+
+	_, ok := view.Get(1)
+	require.False(ok)
+}
@@ -0,0 +1,215 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import "sync"
+
+var (
+	_ Cacher[struct{}, struct{}] = (*ReplicaCache[struct{}, struct{}])(nil)
+	_ BoundReporter              = (*ReplicaCache[struct{}, struct{}])(nil)
+)
+
+type replicaOp uint8
+
+const (
+	replicaPut replicaOp = iota
+	replicaEvict
+	replicaFlush
+)
+
+type replicaUpdate[K comparable, V any] struct {
+	op    replicaOp
+	key   K
+	value V
+}
+
+// ReplicaCache serves reads from its own copy of a primary cache, which it
+// updates asynchronously, so that read-heavy components don't contend on the
+// primary's lock. Writes through the ReplicaCache go to the primary and are
+// queued for the copy. Changes the primary makes on its own, such as
+// evictions to make room, must be relayed with ApplyUpdate and ApplyEvict,
+// typically from the primary's eviction callback, or the copy keeps entries
+// the primary dropped.
+//
+// At most maxLag updates are pending at any time: once the copy falls that far
+// behind, writes block until it catches up. A Get may therefore miss an entry
+// just put, or return one just evicted, but only for the updates that are
+// still pending, as reported by Lag.
+type ReplicaCache[K comparable, V any] struct {
+	primary Cacher[K, V]
+
+	// writeMu orders the writes to the primary the same as their updates.
+	writeMu sync.Mutex
+
+	mu    sync.RWMutex
+	items map[K]V
+
+	updates chan replicaUpdate[K, V]
+	// slots holds a token for every update queued or being applied, so that
+	// its length is the lag.
+	slots chan struct{}
+
+	// closeMu is held shared while an update is queued, so that Close can
+	// drop every update queued before it.
+	closeMu sync.RWMutex
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewReplicaCache starts a ReplicaCache of primary that lags by at most maxLag
+// updates, or 1 if maxLag is lower. The copy starts out empty, so primary
+// should be too. Close must be called to stop it.
+func NewReplicaCache[K comparable, V any](primary Cacher[K, V], maxLag int) *ReplicaCache[K, V] {
+	maxLag = max(maxLag, 1)
+	r := &ReplicaCache[K, V]{
+		primary: primary,
+		items:   make(map[K]V),
+		updates: make(chan replicaUpdate[K, V], maxLag),
+		slots:   make(chan struct{}, maxLag),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+func (r *ReplicaCache[K, V]) run() {
+	defer close(r.done)
+	for {
+		select {
+		case <-r.stop:
+			return
+		case u := <-r.updates:
+			r.apply(u)
+			<-r.slots
+		}
+	}
+}
+
+func (r *ReplicaCache[K, V]) apply(u replicaUpdate[K, V]) {
+	r.mu.Lock()
+	switch u.op {
+	case replicaPut:
+		r.items[u.key] = u.value
+	case replicaEvict:
+		delete(r.items, u.key)
+	case replicaFlush:
+		clear(r.items)
+	}
+	r.mu.Unlock()
+}
+
+// enqueue queues u for the copy, blocking while maxLag updates are pending.
+// Updates queued after Close are dropped.
+func (r *ReplicaCache[K, V]) enqueue(u replicaUpdate[K, V]) {
+	r.closeMu.RLock()
+	defer r.closeMu.RUnlock()
+
+	select {
+	case <-r.stop:
+		return
+	default:
+	}
+	select {
+	case r.slots <- struct{}{}:
+		// Holding a slot guarantees room in updates.
+		r.updates <- u
+	case <-r.stop:
+	}
+}
+
+// ApplyUpdate queues the put of value under key in the copy, without writing
+// to the primary. It is the entry point for relaying changes the primary made
+// itself.
+func (r *ReplicaCache[K, V]) ApplyUpdate(key K, value V) {
+	r.enqueue(replicaUpdate[K, V]{op: replicaPut, key: key, value: value})
+}
+
+// ApplyEvict queues the removal of key from the copy, without writing to the
+// primary. It is the entry point for relaying evictions the primary made
+// itself.
+func (r *ReplicaCache[K, V]) ApplyEvict(key K) {
+	r.enqueue(replicaUpdate[K, V]{op: replicaEvict, key: key})
+}
+
+// Lag returns the number of updates queued or being applied to the copy. It
+// never exceeds the maxLag the cache was created with: writes blocked waiting
+// for room are not counted until they are queued.
+func (r *ReplicaCache[_, _]) Lag() int {
+	return len(r.slots)
+}
+
+// Put inserts value into the primary and queues it for the copy.
+func (r *ReplicaCache[K, V]) Put(key K, value V) {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	r.primary.Put(key, value)
+	r.ApplyUpdate(key, value)
+}
+
+// Get returns the entry with the key in the copy, if it exists.
+func (r *ReplicaCache[K, V]) Get(key K) (V, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	value, ok := r.items[key]
+	return value, ok
+}
+
+// Evict removes key from the primary and queues its removal from the copy.
+func (r *ReplicaCache[K, _]) Evict(key K) {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	r.primary.Evict(key)
+	r.ApplyEvict(key)
+}
+
+// Flush flushes the primary and queues the flush of the copy.
+func (r *ReplicaCache[K, V]) Flush() {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	r.primary.Flush()
+	r.enqueue(replicaUpdate[K, V]{op: replicaFlush})
+}
+
+// Len returns the number of entries in the copy.
+func (r *ReplicaCache[_, _]) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.items)
+}
+
+// PortionFilled returns the portion filled of the primary, as the copy has no
+// capacity of its own.
+func (r *ReplicaCache[_, _]) PortionFilled() float64 {
+	return r.primary.PortionFilled()
+}
+
+// Bounded reports whether the primary is bounded. The copy only stays within
+// the primary's bounds if the primary's evictions are relayed.
+func (r *ReplicaCache[_, _]) Bounded() bool {
+	return IsBounded(r.primary)
+}
+
+// Close stops applying updates and waits for the goroutine applying them to
+// exit. Updates still pending are dropped, so that Lag drops to zero. It is
+// safe to call Close more than once.
+func (r *ReplicaCache[_, _]) Close() {
+	r.once.Do(func() {
+		close(r.stop)
+		// Wait for the updates being queued, which then see stop.
+		r.closeMu.Lock()
+		defer r.closeMu.Unlock()
+		<-r.done
+		for {
+			select {
+			case <-r.updates:
+				<-r.slots
+			default:
+				return
+			}
+		}
+	})
+	<-r.done
+}
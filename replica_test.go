@@ -0,0 +1,120 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"maps"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicaCacheEventuallyConsistent(t *testing.T) {
+	require := require.New(t)
+
+	primary := NewDualMapCache[int, int](nil)
+	r := NewReplicaCache[int, int](primary, 4)
+	defer r.Close()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := (w*1000 + i) % 64
+				switch i % 5 {
+				case 4:
+					r.Evict(key)
+				default:
+					r.Put(key, i)
+				}
+				require.LessOrEqual(r.Lag(), 4)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Eventually(func() bool { return r.Lag() == 0 }, time.Second, time.Millisecond)
+	r.mu.RLock()
+	items := maps.Clone(r.items)
+	r.mu.RUnlock()
+	require.Equal(maps.Collect(primary.All()), items)
+	require.Equal(primary.Len(), r.Len())
+	for k, v := range primary.All() {
+		got, ok := r.Get(k)
+		require.True(ok)
+		require.Equal(v, got)
+	}
+
+	r.Flush()
+	require.Eventually(func() bool { return r.Len() == 0 }, time.Second, time.Millisecond)
+	require.Zero(primary.Len())
+}
+
+func TestReplicaCacheApply(t *testing.T) {
+	require := require.New(t)
+
+	primary := NewDualMapCache[int, string](nil)
+	r := NewReplicaCache[int, string](primary, 1)
+	defer r.Close()
+
+	// Changes the primary makes itself only reach the copy when relayed.
+	primary.Put(1, "a")
+	r.ApplyUpdate(1, "a")
+	require.Eventually(func() bool {
+		v, ok := r.Get(1)
+		return ok && v == "a"
+	}, time.Second, time.Millisecond)
+
+	primary.Evict(1)
+	r.ApplyEvict(1)
+	require.Eventually(func() bool {
+		_, ok := r.Get(1)
+		return !ok
+	}, time.Second, time.Millisecond)
+	require.Zero(r.Lag())
+	require.False(r.Bounded())
+}
+
+func TestReplicaCacheClose(t *testing.T) {
+	require := require.New(t)
+
+	r := NewReplicaCache[int, int](NewDualMapCache[int, int](nil), 1)
+	r.Close()
+	r.Close()
+
+	// Updates after Close are dropped rather than blocking.
+	for i := 0; i < 4; i++ {
+		r.Put(i, i)
+	}
+	require.Zero(r.Lag())
+}
+
+func TestReplicaCacheCloseDropsPending(t *testing.T) {
+	require := require.New(t)
+
+	primary := NewDualMapCache[int, int](nil)
+	r := NewReplicaCache[int, int](primary, 4)
+
+	// Hold the copy's lock so that updates pile up, one being applied and
+	// the others queued.
+	r.mu.Lock()
+	for i := 0; i < 4; i++ {
+		r.Put(i, i)
+	}
+	require.Equal(4, r.Lag())
+
+	closed := make(chan struct{})
+	go func() {
+		r.Close()
+		close(closed)
+	}()
+	r.mu.Unlock()
+	<-closed
+	require.Zero(r.Lag())
+	require.Equal(4, primary.Len())
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"hash/maphash"
+	"iter"
+)
+
+var (
+	_ Cacher[struct{}, struct{}]   = (*ShardedDualMapCache[struct{}, struct{}])(nil)
+	_ Iterable[struct{}, struct{}] = (*ShardedDualMapCache[struct{}, struct{}])(nil)
+	_ StatsReporter                = (*ShardedDualMapCache[struct{}, struct{}])(nil)
+	_ BoundReporter                = (*ShardedDualMapCache[struct{}, struct{}])(nil)
+)
+
+// ShardedDualMapCache spreads its entries over several [DualMapCache] shards
+// chosen by key hash, so that concurrent writers to different keys mostly take
+// different locks. Operations spanning the whole cache, such as Len, Flush and
+// All, visit the shards one at a time and are not atomic across them.
+type ShardedDualMapCache[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []*DualMapCache[K, V]
+}
+
+// NewDualMapCacheSharded creates a cache split into the given number of
+// shards. A count below one is treated as one.
+func NewDualMapCacheSharded[K comparable, V any](shards int) *ShardedDualMapCache[K, V] {
+	shards = max(shards, 1)
+	c := &ShardedDualMapCache[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*DualMapCache[K, V], shards),
+	}
+	for i := range c.shards {
+		c.shards[i] = NewDualMapCache[K, V](nil)
+	}
+	return c
+}
+
+func (c *ShardedDualMapCache[K, V]) shard(key K) *DualMapCache[K, V] {
+	if len(c.shards) == 1 {
+		return c.shards[0]
+	}
+	h := maphash.Comparable(c.seed, key)
+	return c.shards[h%uint64(len(c.shards))]
+}
+
+// Put inserts or replaces an element in the cache.
+func (c *ShardedDualMapCache[K, V]) Put(key K, value V) {
+	c.shard(key).Put(key, value)
+}
+
+// Get returns the entry with the key, if it exists.
+func (c *ShardedDualMapCache[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).Get(key)
+}
+
+// GetOrDefault returns the value of key, or def if the key is missing.
+func (c *ShardedDualMapCache[K, V]) GetOrDefault(key K, def V) V {
+	return c.shard(key).GetOrDefault(key, def)
+}
+
+// GetOrZero returns the value of key, or the zero value if the key is missing.
+func (c *ShardedDualMapCache[K, V]) GetOrZero(key K) V {
+	return c.shard(key).GetOrZero(key)
+}
+
+// Evict removes the specified entry from the cache.
+func (c *ShardedDualMapCache[K, V]) Evict(key K) {
+	c.shard(key).Evict(key)
+}
+
+// DelGet removes key and returns its value, reporting whether it was present,
+// under the lock of the key's shard.
+func (c *ShardedDualMapCache[K, V]) DelGet(key K) (V, bool) {
+	return c.shard(key).DelGet(key)
+}
+
+// EvictMany removes every listed key and returns the number of entries
+// removed. Missing and repeated keys are ignored.
+func (c *ShardedDualMapCache[K, V]) EvictMany(keys []K) int {
+	removed := 0
+	for _, key := range keys {
+		s := c.shard(key)
+		s.mu.Lock()
+		if _, ok := s.items[key]; ok {
+			delete(s.items, key)
+			removed++
+		}
+		s.mu.Unlock()
+	}
+	return removed
+}
+
+// EvictFunc removes every entry for which pred returns true and returns the
+// number of entries removed, holding each shard's lock while that shard is
+// scanned. pred must not call back into the cache.
+func (c *ShardedDualMapCache[K, V]) EvictFunc(pred func(K, V) bool) int {
+	removed := 0
+	for _, s := range c.shards {
+		removed += s.EvictFunc(pred)
+	}
+	return removed
+}
+
+// All returns an iterator over the entries of the cache in no particular
+// order. Each shard is snapshotted when iteration reaches it, so the loop body
+// may safely call back into the cache.
+func (c *ShardedDualMapCache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, s := range c.shards {
+			for key, value := range s.All() {
+				if !yield(key, value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Compact compacts each shard in turn, as [DualMapCache.Compact] does.
+func (c *ShardedDualMapCache[K, V]) Compact() {
+	for _, s := range c.shards {
+		s.Compact()
+	}
+}
+
+// Flush removes all entries from the cache.
+func (c *ShardedDualMapCache[K, V]) Flush() {
+	for _, s := range c.shards {
+		s.Flush()
+	}
+}
+
+// Len returns the number of elements in the cache, summed over the shards.
+func (c *ShardedDualMapCache[K, V]) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.Len()
+	}
+	return n
+}
+
+// PortionFilled returns fraction of cache currently filled.
+func (c *ShardedDualMapCache[K, V]) PortionFilled() float64 {
+	return portionFilled(c.Len())
+}
+
+// Bounded returns false, like [DualMapCache.Bounded].
+func (*ShardedDualMapCache[_, _]) Bounded() bool {
+	return false
+}
+
+// Stats returns the length of the cache, like [DualMapCache.Stats].
+func (c *ShardedDualMapCache[K, V]) Stats() Stats {
+	n := c.Len()
+	return Stats{
+		Hits:          Untracked,
+		Misses:        Untracked,
+		Evictions:     Untracked,
+		Len:           n,
+		Cap:           Untracked,
+		PortionFilled: portionFilled(n),
+	}
+}
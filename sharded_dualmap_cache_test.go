@@ -0,0 +1,104 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"maps"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardedDualMapCache(t *testing.T) {
+	require := require.New(t)
+
+	c := NewDualMapCacheSharded[int, string](8)
+	for i := range 100 {
+		c.Put(i, strconv.Itoa(i))
+	}
+	require.Equal(100, c.Len())
+	require.Equal(1.0, c.PortionFilled())
+
+	v, ok := c.Get(42)
+	require.True(ok)
+	require.Equal("42", v)
+	require.Equal("none", c.GetOrDefault(100, "none"))
+
+	c.Evict(42)
+	_, ok = c.Get(42)
+	require.False(ok)
+
+	require.Equal(2, c.EvictMany([]int{1, 2, 2, 100}))
+	require.Equal(97, c.Len())
+
+	removed := c.EvictFunc(func(k int, _ string) bool {
+		return k >= 50
+	})
+	require.Equal(50, removed)
+
+	all := maps.Collect(c.All())
+	require.Len(all, 47)
+	require.Equal("0", all[0])
+
+	c.Flush()
+	require.Zero(c.Len())
+	require.Zero(c.PortionFilled())
+}
+
+func TestShardedDualMapCacheMinimumShards(t *testing.T) {
+	require := require.New(t)
+
+	c := NewDualMapCacheSharded[string, int](0)
+	require.Len(c.shards, 1)
+	c.Put("a", 1)
+	require.Equal(1, c.GetOrZero("a"))
+}
+
+func TestShardedDualMapCacheConcurrentPut(t *testing.T) {
+	c := NewDualMapCacheSharded[int, int](16)
+
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				c.Put(w*1000+i, i)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 8000, c.Len())
+}
+
+// BenchmarkDualMapCacheContention compares a single-lock cache with a sharded
+// one under concurrent writers to distinct keys.
+func BenchmarkDualMapCacheContention(b *testing.B) {
+	for _, bc := range []struct {
+		name  string
+		cache Cacher[int, int]
+	}{
+		{name: "single", cache: NewDualMapCache[int, int](nil)},
+		{name: "sharded", cache: NewDualMapCacheSharded[int, int](64)},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			var next sync.Mutex
+			worker := 0
+			b.RunParallel(func(pb *testing.PB) {
+				next.Lock()
+				base := worker << 20
+				worker++
+				next.Unlock()
+
+				i := 0
+				for pb.Next() {
+					bc.cache.Put(base+i&0xffff, i)
+					i++
+				}
+			})
+		})
+	}
+}
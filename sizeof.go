@@ -0,0 +1,129 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+const (
+	sliceHeaderSize  = int(unsafe.Sizeof([]byte(nil)))
+	stringHeaderSize = int(unsafe.Sizeof(""))
+
+	// mapHeaderSize approximates the fixed allocation of a non-nil map, and
+	// mapSlotOverhead the per-entry control byte of its hash table, which is
+	// kept at most 7/8 full.
+	mapHeaderSize   = 48
+	mapSlotOverhead = 1
+)
+
+// SizeOfBytes returns the number of bytes retained by b, counting its header
+// and the full capacity of its backing array. It can be used to build the
+// sizeFn of a size-bounded cache.
+func SizeOfBytes(b []byte) int {
+	return sliceHeaderSize + cap(b)
+}
+
+// SizeOfString returns the number of bytes retained by s, counting its header
+// and contents.
+func SizeOfString(s string) int {
+	return stringHeaderSize + len(s)
+}
+
+// EstimateSize approximates the number of heap bytes retained by v, following
+// pointers, slices, strings, maps, interfaces and the fields of structs. It is
+// meant as a reasonable default sizeFn and is considerably slower than a
+// hand-written one.
+//
+// The estimate is approximate:
+//   - Map sizes assume a fully grown table at the maximum load factor and
+//     ignore the runtime's internal layout, so they may be off by a factor of
+//     two.
+//   - Allocator size classes and padding between allocations are ignored.
+//   - Strings and slices sharing memory are counted once per reference, while
+//     memory reachable through the same pointer twice is counted once.
+//   - Channels, functions and unsafe pointers count only their own word,
+//     not what they reference.
+func EstimateSize(v any) int {
+	if v == nil {
+		return 0
+	}
+	rv := reflect.ValueOf(v)
+	e := sizeEstimator{seen: make(map[uintptr]struct{})}
+	return int(rv.Type().Size()) + e.indirect(rv)
+}
+
+// sizeEstimator tracks the pointers already followed so that shared and
+// cyclic structures are counted once.
+type sizeEstimator struct {
+	seen map[uintptr]struct{}
+}
+
+// visit reports whether ptr has not been followed before, marking it as
+// followed.
+func (e *sizeEstimator) visit(ptr uintptr) bool {
+	if _, ok := e.seen[ptr]; ok {
+		return false
+	}
+	e.seen[ptr] = struct{}{}
+	return true
+}
+
+// indirect returns the bytes referenced by v beyond its inline size.
+func (e *sizeEstimator) indirect(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || !e.visit(v.Pointer()) {
+			return 0
+		}
+		elem := v.Elem()
+		return int(elem.Type().Size()) + e.indirect(elem)
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		elem := v.Elem()
+		if elem.Kind() == reflect.Pointer {
+			return e.indirect(elem)
+		}
+		return int(elem.Type().Size()) + e.indirect(elem)
+	case reflect.String:
+		return v.Len()
+	case reflect.Slice:
+		if v.IsNil() {
+			return 0
+		}
+		size := v.Cap() * int(v.Type().Elem().Size())
+		for i := range v.Len() {
+			size += e.indirect(v.Index(i))
+		}
+		return size
+	case reflect.Array:
+		size := 0
+		for i := range v.Len() {
+			size += e.indirect(v.Index(i))
+		}
+		return size
+	case reflect.Struct:
+		size := 0
+		for i := range v.NumField() {
+			size += e.indirect(v.Field(i))
+		}
+		return size
+	case reflect.Map:
+		if v.IsNil() || !e.visit(v.Pointer()) {
+			return 0
+		}
+		slot := int(v.Type().Key().Size()+v.Type().Elem().Size()) + mapSlotOverhead
+		size := mapHeaderSize + v.Len()*slot*8/7
+		iter := v.MapRange()
+		for iter.Next() {
+			size += e.indirect(iter.Key()) + e.indirect(iter.Value())
+		}
+		return size
+	default:
+		return 0
+	}
+}
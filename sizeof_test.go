@@ -0,0 +1,75 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeOf(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(24+8, SizeOfBytes(make([]byte, 4, 8)))
+	require.Equal(24, SizeOfBytes(nil))
+	require.Equal(16+5, SizeOfString("hello"))
+}
+
+func TestEstimateSize(t *testing.T) {
+	type node struct {
+		Value int64
+		Next  *node
+	}
+	cyclic := &node{Value: 1}
+	cyclic.Next = cyclic
+
+	type record struct {
+		Name  string
+		Data  []byte
+		inner *int64
+	}
+	n := int64(7)
+
+	tests := []struct {
+		name string
+		v    any
+		want int
+	}{
+		{name: "nil", v: nil, want: 0},
+		{name: "int", v: int64(1), want: 8},
+		{name: "string", v: "hello", want: 16 + 5},
+		{name: "bytes", v: make([]byte, 3, 10), want: 24 + 10},
+		{name: "string slice", v: []string{"ab", "cde"}, want: 24 + 2*16 + 5},
+		{name: "pointer", v: &n, want: 8 + 8},
+		{name: "cycle", v: cyclic, want: 8 + 16},
+		{
+			name: "struct",
+			v:    record{Name: "abc", Data: []byte{1, 2}, inner: &n},
+			want: 16 + 24 + 8 + 3 + 2 + 8,
+		},
+		{name: "interface slice", v: []any{int64(1), "ab"}, want: 24 + 2*16 + 8 + 16 + 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, EstimateSize(test.v))
+		})
+	}
+}
+
+func TestEstimateSizeMap(t *testing.T) {
+	require := require.New(t)
+
+	small := EstimateSize(map[int64]int64{1: 1})
+	large := EstimateSize(map[int64]int64{1: 1, 2: 2, 3: 3, 4: 4, 5: 5, 6: 6, 7: 7, 8: 8})
+	require.Greater(small, 8+mapHeaderSize)
+	// Each entry holds a 16 byte key and value.
+	require.GreaterOrEqual(large-small, 7*16)
+
+	// The contents of keys and values are counted too.
+	require.Greater(
+		EstimateSize(map[string]string{"k": "a long value"}),
+		EstimateSize(map[string]string{"k": ""}),
+	)
+}
@@ -0,0 +1,31 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cache
+
+// Untracked is reported in the fields of [Stats] that a cache does not track.
+const Untracked = -1
+
+// Stats is a snapshot of a cache's counters in a shape shared by every cache
+// implementation. Counters a cache does not track are set to [Untracked].
+type Stats struct {
+	// Hits is the number of lookups that found their key.
+	Hits int64
+	// Misses is the number of lookups that did not find their key.
+	Misses int64
+	// Evictions is the number of entries removed to make room for others.
+	Evictions int64
+	// Len is the number of entries in the cache.
+	Len int
+	// Cap is the capacity of the cache, in the unit it is bounded by, or
+	// [Untracked] if it is unbounded.
+	Cap int
+	// PortionFilled is the fraction of the cache currently filled.
+	PortionFilled float64
+}
+
+// StatsReporter is implemented by caches that report their counters as
+// [Stats].
+type StatsReporter interface {
+	Stats() Stats
+}
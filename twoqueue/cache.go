@@ -0,0 +1,218 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package twoqueue provides a 2Q cache, which resists pollution from one-off
+// scans better than plain LRU while being simpler than ARC.
+package twoqueue
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/luxfi/cache"
+)
+
+const (
+	// DefaultRecentRatio is the default fraction of the cache size used by the
+	// A1in queue of recently added entries.
+	DefaultRecentRatio = 0.25
+	// DefaultGhostRatio is the default number of evicted keys remembered by
+	// the A1out ghost queue, as a fraction of the cache size.
+	DefaultGhostRatio = 0.50
+)
+
+var (
+	_ cache.Cacher[struct{}, struct{}] = (*Cache[struct{}, struct{}])(nil)
+	_ cache.BoundReporter              = (*Cache[struct{}, struct{}])(nil)
+)
+
+// Cache is a 2Q cache.
+//
+// New keys enter the A1in FIFO queue. Keys that fall out of A1in are
+// remembered, without their values, in the A1out ghost queue. A key that is
+// put again while it is in A1out is considered hot and is promoted to the Am
+// LRU queue. Hits in A1in do not change its order, so a single scan over many
+// keys only ever displaces other entries of A1in.
+type Cache[K comparable, V any] struct {
+	mu sync.Mutex
+
+	size       int
+	recentSize int
+	ghostSize  int
+
+	items map[K]*list.Element
+	// recent is A1in, frequent is Am and ghost is A1out. The front of each list
+	// is the most recently inserted or used entry.
+	recent   *list.List
+	frequent *list.List
+	ghost    *list.List
+	ghosts   map[K]*list.Element
+}
+
+type entry[K comparable, V any] struct {
+	key      K
+	value    V
+	frequent bool
+}
+
+// NewCache creates a 2Q cache holding up to size entries with the default
+// queue ratios.
+func NewCache[K comparable, V any](size int) *Cache[K, V] {
+	return NewCacheWithRatios[K, V](size, DefaultRecentRatio, DefaultGhostRatio)
+}
+
+// NewCacheWithRatios creates a 2Q cache holding up to size entries, where
+// recentRatio*size entries are reserved for A1in and up to ghostRatio*size
+// evicted keys are remembered in A1out. Ratios outside of [0, 1] are replaced
+// with their defaults.
+func NewCacheWithRatios[K comparable, V any](size int, recentRatio, ghostRatio float64) *Cache[K, V] {
+	if size <= 0 {
+		size = 1
+	}
+	if recentRatio < 0 || recentRatio > 1 {
+		recentRatio = DefaultRecentRatio
+	}
+	if ghostRatio < 0 || ghostRatio > 1 {
+		ghostRatio = DefaultGhostRatio
+	}
+	return &Cache[K, V]{
+		size:       size,
+		recentSize: int(float64(size) * recentRatio),
+		ghostSize:  int(float64(size) * ghostRatio),
+		items:      make(map[K]*list.Element),
+		recent:     list.New(),
+		frequent:   list.New(),
+		ghost:      list.New(),
+		ghosts:     make(map[K]*list.Element),
+	}
+}
+
+// Put inserts or replaces an element in the cache.
+func (c *Cache[K, V]) Put(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry[K, V])
+		e.value = value
+		if e.frequent {
+			c.frequent.MoveToFront(elem)
+		}
+		return
+	}
+
+	if elem, ok := c.ghosts[key]; ok {
+		c.ghost.Remove(elem)
+		delete(c.ghosts, key)
+		c.reclaim()
+		c.items[key] = c.frequent.PushFront(&entry[K, V]{
+			key:      key,
+			value:    value,
+			frequent: true,
+		})
+		return
+	}
+
+	c.reclaim()
+	c.items[key] = c.recent.PushFront(&entry[K, V]{
+		key:   key,
+		value: value,
+	})
+}
+
+// Get returns the entry with the key, if it exists.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	e := elem.Value.(*entry[K, V])
+	if e.frequent {
+		c.frequent.MoveToFront(elem)
+	}
+	return e.value, true
+}
+
+// Evict removes the specified entry from the cache.
+func (c *Cache[K, V]) Evict(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.remove(elem)
+	}
+	if elem, ok := c.ghosts[key]; ok {
+		c.ghost.Remove(elem)
+		delete(c.ghosts, key)
+	}
+}
+
+// Flush removes all entries from the cache, including remembered ghosts.
+func (c *Cache[K, V]) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[K]*list.Element)
+	c.ghosts = make(map[K]*list.Element)
+	c.recent.Init()
+	c.frequent.Init()
+	c.ghost.Init()
+}
+
+// Len returns the number of elements in the cache. Ghost keys aren't counted.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// PortionFilled returns fraction of cache currently filled (0 --> 1).
+func (c *Cache[K, V]) PortionFilled() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return float64(len(c.items)) / float64(c.size)
+}
+
+// reclaim makes room for one new entry if the cache is full. Entries are
+// taken from A1in while it exceeds its share, moving their keys to A1out, and
+// from the tail of Am otherwise.
+func (c *Cache[K, V]) reclaim() {
+	if len(c.items) < c.size {
+		return
+	}
+	if c.recent.Len() > c.recentSize || c.frequent.Len() == 0 {
+		back := c.recent.Back()
+		key := c.remove(back).key
+		if c.ghostSize == 0 {
+			return
+		}
+		c.ghosts[key] = c.ghost.PushFront(key)
+		if c.ghost.Len() > c.ghostSize {
+			oldest := c.ghost.Back()
+			c.ghost.Remove(oldest)
+			delete(c.ghosts, oldest.Value.(K))
+		}
+		return
+	}
+	c.remove(c.frequent.Back())
+}
+
+func (c *Cache[K, V]) remove(elem *list.Element) *entry[K, V] {
+	e := elem.Value.(*entry[K, V])
+	if e.frequent {
+		c.frequent.Remove(elem)
+	} else {
+		c.recent.Remove(elem)
+	}
+	delete(c.items, e.key)
+	return e
+}
+
+// Bounded returns true, as the cache holds at most size entries.
+func (*Cache[_, _]) Bounded() bool {
+	return true
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2026, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package twoqueue
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxfi/cache"
+	"github.com/luxfi/cache/lru"
+)
+
+func TestPromotionFromGhost(t *testing.T) {
+	require := require.New(t)
+
+	// 4 entries with 1 reserved for A1in and 2 ghosts.
+	c := NewCache[int, int](4)
+	for i := 0; i < 4; i++ {
+		c.Put(i, i)
+	}
+	require.Equal(4, c.Len())
+
+	// Key 0 falls out of A1in and is remembered as a ghost.
+	c.Put(4, 4)
+	_, ok := c.Get(0)
+	require.False(ok)
+	require.Contains(c.ghosts, 0)
+
+	// Putting a ghost key promotes it to Am.
+	c.Put(0, 0)
+	require.NotContains(c.ghosts, 0)
+	elem, ok := c.items[0]
+	require.True(ok)
+	require.True(elem.Value.(*entry[int, int]).frequent)
+
+	// A scan of new keys only displaces A1in entries.
+	for i := 100; i < 110; i++ {
+		c.Put(i, i)
+	}
+	v, ok := c.Get(0)
+	require.True(ok)
+	require.Zero(v)
+	require.Equal(4, c.Len())
+	require.LessOrEqual(c.ghost.Len(), 2)
+}
+
+func TestCacherOperations(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCacheWithRatios[string, string](2, 0.5, 0.5)
+	c.Put("a", "apple")
+	c.Put("a", "avocado")
+	v, ok := c.Get("a")
+	require.True(ok)
+	require.Equal("avocado", v)
+	require.Equal(0.5, c.PortionFilled())
+
+	c.Evict("a")
+	require.Zero(c.Len())
+
+	c.Put("b", "banana")
+	c.Flush()
+	require.Zero(c.Len())
+	require.Zero(c.PortionFilled())
+}
+
+func BenchmarkHitRatio(b *testing.B) {
+	const (
+		size    = 1000
+		numKeys = 10_000
+	)
+	constructors := map[string]func() cache.Cacher[int, int]{
+		"lru":      func() cache.Cacher[int, int] { return lru.NewCache[int, int](size) },
+		"twoqueue": func() cache.Cacher[int, int] { return NewCache[int, int](size) },
+	}
+	for name, newCache := range constructors {
+		b.Run(name, func(b *testing.B) {
+			c := newCache()
+			r := rand.New(rand.NewSource(0))
+			zipf := rand.NewZipf(r, 1.1, 1, numKeys-1)
+			var hits, scan int
+			for i := 0; i < b.N; i++ {
+				// Every fourth access is part of a scan over keys that are
+				// never reused.
+				var key int
+				if i%4 == 0 {
+					scan++
+					key = numKeys + scan
+				} else {
+					key = int(zipf.Uint64())
+				}
+				if _, ok := c.Get(key); ok {
+					hits++
+					continue
+				}
+				c.Put(key, key)
+			}
+			b.ReportMetric(float64(hits)/float64(b.N), "hit-ratio")
+		})
+	}
+}
+
+func TestBounded(t *testing.T) {
+	require.True(t, cache.IsBounded[int, int](NewCache[int, int](2)))
+}